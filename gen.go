@@ -1,3 +1,4 @@
 package simon
 
 //go:generate go run github.com/ogen-go/ogen/cmd/ogen --target internal/oas -package oas --clean _oas/openapi.yaml
+//go:generate go run github.com/bufbuild/buf/cmd/buf@v1.50.0 generate _proto --template _proto/buf.gen.yaml
@@ -0,0 +1,206 @@
+// Package auth implements authentication of simon API.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/ogen-go/ogen/ogenerrors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-faster/simon/internal/oas"
+)
+
+var (
+	// ErrUnauthorized means missing or invalid credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden means valid credentials without required scope.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Authentication schemes.
+const (
+	SchemeAPIKey = "api_key"
+	SchemeBearer = "bearer"
+	SchemeJWT    = "jwt"
+)
+
+// Options of Authenticator, auth is disabled if no credentials are set.
+type Options struct {
+	APIKeys []string
+	Tokens  []string
+	// JWTKey enables validation of bearer tokens as HS256 JWT,
+	// requiring Scope.
+	JWTKey []byte
+	Scope  string
+	// Latency simulates remote auth service.
+	Latency time.Duration
+}
+
+// Principal is authenticated client.
+type Principal struct {
+	Scheme  string
+	Subject string
+}
+
+type principalKey struct{}
+
+// PrincipalFrom returns principal from context.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Authenticator implements oas.SecurityHandler.
+type Authenticator struct {
+	opts   Options
+	tracer trace.Tracer
+
+	attempts metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+var _ oas.SecurityHandler = (*Authenticator)(nil)
+
+// New creates new Authenticator.
+func New(opts Options, tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Authenticator, error) {
+	a := &Authenticator{
+		opts:   opts,
+		tracer: tracerProvider.Tracer("simon.auth"),
+	}
+	meter := meterProvider.Meter("simon.auth")
+	var err error
+	if a.attempts, err = meter.Int64Counter("simon.auth.attempts",
+		metric.WithDescription("Number of authentication attempts"),
+	); err != nil {
+		return nil, errors.Wrap(err, "attempts")
+	}
+	if a.duration, err = meter.Float64Histogram("simon.auth.duration",
+		metric.WithDescription("Duration of authentication"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, errors.Wrap(err, "duration")
+	}
+	return a, nil
+}
+
+// Enabled reports whether authentication is required.
+func (a *Authenticator) Enabled() bool {
+	return len(a.opts.APIKeys) > 0 || len(a.opts.Tokens) > 0 || len(a.opts.JWTKey) > 0
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if subtle.ConstantTimeCompare([]byte(s), []byte(v)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate runs check in auth span, recording result.
+func (a *Authenticator) authenticate(ctx context.Context, scheme string, check func() (Principal, error)) (context.Context, error) {
+	start := time.Now()
+	ctx, span := a.tracer.Start(ctx, "auth."+scheme, trace.WithAttributes(
+		attribute.String("simon.auth.scheme", scheme),
+	))
+	defer span.End()
+
+	if a.opts.Latency > 0 {
+		timer := time.NewTimer(a.opts.Latency)
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+
+	p, err := check()
+	result := "ok"
+	switch {
+	case errors.Is(err, ErrForbidden):
+		result = "forbidden"
+	case errors.Is(err, errExpired):
+		result = "expired"
+	case err != nil:
+		result = "invalid"
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("scheme", scheme),
+		attribute.String("result", result),
+	}
+	a.attempts.Add(ctx, 1, metric.WithAttributes(attrs...))
+	a.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	span.SetAttributes(attribute.String("simon.auth.result", result))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, result)
+		return ctx, err
+	}
+	if p.Subject != "" {
+		span.SetAttributes(attribute.String("enduser.id", p.Subject))
+	}
+	return context.WithValue(ctx, principalKey{}, p), nil
+}
+
+// HandleApiKey implements oas.SecurityHandler.
+func (a *Authenticator) HandleApiKey(ctx context.Context, _ oas.OperationName, t oas.ApiKey) (context.Context, error) {
+	if !a.Enabled() {
+		return ctx, ogenerrors.ErrSkipServerSecurity
+	}
+	return a.authenticate(ctx, SchemeAPIKey, func() (Principal, error) {
+		if !contains(a.opts.APIKeys, t.APIKey) {
+			return Principal{}, errors.Wrap(ErrUnauthorized, "unknown api key")
+		}
+		return Principal{Scheme: SchemeAPIKey}, nil
+	})
+}
+
+// HandleBearerAuth implements oas.SecurityHandler.
+func (a *Authenticator) HandleBearerAuth(ctx context.Context, _ oas.OperationName, t oas.BearerAuth) (context.Context, error) {
+	if !a.Enabled() {
+		return ctx, ogenerrors.ErrSkipServerSecurity
+	}
+	if contains(a.opts.Tokens, t.Token) {
+		return a.authenticate(ctx, SchemeBearer, func() (Principal, error) {
+			return Principal{Scheme: SchemeBearer}, nil
+		})
+	}
+	if len(a.opts.JWTKey) == 0 {
+		return a.authenticate(ctx, SchemeBearer, func() (Principal, error) {
+			return Principal{}, errors.Wrap(ErrUnauthorized, "unknown token")
+		})
+	}
+	return a.authenticate(ctx, SchemeJWT, func() (Principal, error) {
+		c, err := Verify(a.opts.JWTKey, t.Token, time.Now())
+		if err != nil {
+			return Principal{}, errors.Wrap(ErrUnauthorized, err.Error())
+		}
+		if a.opts.Scope != "" && !c.HasScope(a.opts.Scope) {
+			return Principal{}, errors.Wrapf(ErrForbidden, "missing scope %q", a.opts.Scope)
+		}
+		return Principal{Scheme: SchemeJWT, Subject: c.Subject}, nil
+	})
+}
+
+// Require returns error if authentication is enabled but context has
+// no principal, i.e. request had no credentials.
+func (a *Authenticator) Require(ctx context.Context) error {
+	if !a.Enabled() {
+		return nil
+	}
+	if _, ok := PrincipalFrom(ctx); ok {
+		return nil
+	}
+	a.attempts.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("scheme", "none"),
+		attribute.String("result", "missing"),
+	))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("simon.auth.result", "missing"))
+	return errors.Wrap(ErrUnauthorized, "missing credentials")
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-faster/simon/internal/oas"
+)
+
+// gRPC metadata keys of credentials, same as HTTP headers.
+const (
+	metadataAuthorization = "authorization"
+	metadataAPIKey        = "x-api-key"
+)
+
+var _ credentials.PerRPCCredentials = (*Source)(nil)
+
+// GetRequestMetadata implements credentials.PerRPCCredentials, preferring
+// bearer token over API key like HTTP client does.
+func (s *Source) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	if t, err := s.BearerAuth(ctx, oas.UploadFileOperation); err == nil {
+		return map[string]string{metadataAuthorization: "Bearer " + t.Token}, nil
+	}
+	if k, err := s.ApiKey(ctx, oas.UploadFileOperation); err == nil {
+		return map[string]string{metadataAPIKey: k.APIKey}, nil
+	}
+	return nil, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (s *Source) RequireTransportSecurity() bool {
+	return false
+}
+
+// metadataContext authenticates credentials of incoming gRPC metadata.
+func (a *Authenticator) metadataContext(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(metadataAuthorization); len(v) > 0 {
+		token, ok := strings.CutPrefix(v[0], "Bearer ")
+		if !ok {
+			return ctx, errors.Wrap(ErrUnauthorized, "invalid authorization scheme")
+		}
+		return a.HandleBearerAuth(ctx, oas.UploadFileOperation, oas.BearerAuth{Token: token})
+	}
+	if v := md.Get(metadataAPIKey); len(v) > 0 {
+		return a.HandleApiKey(ctx, oas.UploadFileOperation, oas.ApiKey{APIKey: v[0]})
+	}
+	return ctx, nil
+}
+
+func grpcError(err error) error {
+	code := codes.Unauthenticated
+	if errors.Is(err, ErrForbidden) {
+		code = codes.PermissionDenied
+	}
+	return status.Error(code, err.Error())
+}
+
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authStream) Context() context.Context { return s.ctx }
+
+// StreamInterceptor authenticates streaming calls of methods by metadata
+// credentials, requiring them like UploadFile does over HTTP.
+func (a *Authenticator) StreamInterceptor(methods ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !a.Enabled() || !slices.Contains(methods, info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := a.metadataContext(ss.Context())
+		if err == nil {
+			err = a.Require(ctx)
+		}
+		if err != nil {
+			return grpcError(err)
+		}
+		return handler(srv, authStream{ServerStream: ss, ctx: ctx})
+	}
+}
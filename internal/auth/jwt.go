@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// Claims of JWT.
+type Claims struct {
+	Subject   string `json:"sub,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	NotBefore int64  `json:"nbf,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// HasScope reports whether space-separated scope contains s.
+func (c Claims) HasScope(s string) bool {
+	for _, v := range strings.Fields(c.Scope) {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	errMalformed = errors.New("malformed token")
+	errAlgorithm = errors.New("unsupported algorithm")
+	errSignature = errors.New("invalid signature")
+	errExpired   = errors.New("token expired")
+	errNotYet    = errors.New("token not valid yet")
+)
+
+var (
+	encoding = base64.RawURLEncoding
+	// Only HS256 is supported.
+	jwtHeader = encoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+)
+
+func signature(key []byte, unsigned string) string {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(unsigned))
+	return encoding.EncodeToString(h.Sum(nil))
+}
+
+// Sign claims with HS256.
+func Sign(key []byte, c Claims) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.Wrap(err, "marshal claims")
+	}
+	unsigned := jwtHeader + "." + encoding.EncodeToString(payload)
+	return unsigned + "." + signature(key, unsigned), nil
+}
+
+// Verify HS256 token, returning its claims.
+func Verify(key []byte, token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errMalformed
+	}
+	header, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errMalformed
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil {
+		return nil, errMalformed
+	}
+	if h.Alg != "HS256" {
+		return nil, errors.Wrapf(errAlgorithm, "alg %q", h.Alg)
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(signature(key, parts[0]+"."+parts[1]))) {
+		return nil, errSignature
+	}
+	payload, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errMalformed
+	}
+	var c Claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, errMalformed
+	}
+	if c.ExpiresAt != 0 && now.Unix() >= c.ExpiresAt {
+		return nil, errExpired
+	}
+	if c.NotBefore != 0 && now.Unix() < c.NotBefore {
+		return nil, errNotYet
+	}
+	return &c, nil
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/go-faster/simon/internal/oas"
+)
+
+// token returns token of raw header and payload JSON signed by key.
+func token(key []byte, header, payload string) string {
+	unsigned := encoding.EncodeToString([]byte(header)) + "." + encoding.EncodeToString([]byte(payload))
+	return unsigned + "." + signature(key, unsigned)
+}
+
+func sign(t *testing.T, key []byte, c Claims) string {
+	t.Helper()
+	s, err := Sign(key, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestVerify(t *testing.T) {
+	var (
+		key   = []byte("secret")
+		now   = time.Unix(1_700_000_000, 0)
+		valid = sign(t, key, Claims{Subject: "simon", Scope: "upload"})
+	)
+	// Replaces last signature character, keeping it valid base64.
+	tampered := valid[:len(valid)-1] + "A"
+	if tampered == valid {
+		tampered = valid[:len(valid)-1] + "B"
+	}
+
+	for _, tt := range []struct {
+		name    string
+		key     []byte
+		token   string
+		wantErr error
+	}{
+		{name: "Valid", token: valid},
+		{name: "TamperedSignature", token: tampered, wantErr: errSignature},
+		{name: "TamperedPayload", token: jwtHeader + "." + encoding.EncodeToString([]byte(`{"scope":"upload admin"}`)) + "." + valid[len(valid)-43:], wantErr: errSignature},
+		{name: "WrongKey", key: []byte("other"), token: valid, wantErr: errSignature},
+		{name: "EmptySignature", token: valid[:len(valid)-43], wantErr: errSignature},
+		{name: "AlgNone", token: token(key, `{"alg":"none","typ":"JWT"}`, `{"sub":"simon"}`), wantErr: errAlgorithm},
+		{name: "AlgNoneUnsigned", token: encoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + encoding.EncodeToString([]byte(`{"sub":"simon"}`)) + ".", wantErr: errAlgorithm},
+		{name: "AlgHS512", token: token(key, `{"alg":"HS512","typ":"JWT"}`, `{"sub":"simon"}`), wantErr: errAlgorithm},
+		{name: "AlgRS256", token: token(key, `{"alg":"RS256","typ":"JWT"}`, `{"sub":"simon"}`), wantErr: errAlgorithm},
+		{name: "AlgMissing", token: token(key, `{"typ":"JWT"}`, `{"sub":"simon"}`), wantErr: errAlgorithm},
+		{name: "TwoSegments", token: jwtHeader + "." + encoding.EncodeToString([]byte(`{}`)), wantErr: errMalformed},
+		{name: "FourSegments", token: valid + ".x", wantErr: errMalformed},
+		{name: "Empty", token: "", wantErr: errMalformed},
+		{name: "HeaderBase64", token: "!!!." + encoding.EncodeToString([]byte(`{}`)) + ".x", wantErr: errMalformed},
+		{name: "HeaderJSON", token: token(key, `not json`, `{}`), wantErr: errMalformed},
+		{name: "PayloadBase64", token: jwtHeader + ".!!!." + signature(key, jwtHeader+".!!!"), wantErr: errMalformed},
+		{name: "PayloadJSON", token: token(key, `{"alg":"HS256"}`, `not json`), wantErr: errMalformed},
+		{name: "BeforeExpiry", token: sign(t, key, Claims{ExpiresAt: now.Unix() + 1})},
+		{name: "AtExpiry", token: sign(t, key, Claims{ExpiresAt: now.Unix()}), wantErr: errExpired},
+		{name: "AfterExpiry", token: sign(t, key, Claims{ExpiresAt: now.Unix() - 1}), wantErr: errExpired},
+		{name: "BeforeNotBefore", token: sign(t, key, Claims{NotBefore: now.Unix() + 1}), wantErr: errNotYet},
+		{name: "AtNotBefore", token: sign(t, key, Claims{NotBefore: now.Unix()})},
+		{name: "AfterNotBefore", token: sign(t, key, Claims{NotBefore: now.Unix() - 1})},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			k := key
+			if tt.key != nil {
+				k = tt.key
+			}
+			c, err := Verify(k, tt.token, now)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if c != nil {
+					t.Fatalf("expected no claims, got %+v", c)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c == nil {
+				t.Fatal("expected claims")
+			}
+		})
+	}
+}
+
+func TestHandleBearerAuthJWT(t *testing.T) {
+	key := []byte("secret")
+	a, err := New(Options{JWTKey: key, Scope: "upload"}, tracenoop.NewTracerProvider(), metricnoop.NewMeterProvider())
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Unix()
+
+	for _, tt := range []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "Valid", token: sign(t, key, Claims{Subject: "simon", Scope: "read upload", ExpiresAt: now + 60})},
+		{name: "MissingScope", token: sign(t, key, Claims{Subject: "simon", Scope: "read"}), wantErr: ErrForbidden},
+		{name: "Expired", token: sign(t, key, Claims{Scope: "upload", ExpiresAt: now - 60}), wantErr: ErrUnauthorized},
+		{name: "WrongKey", token: sign(t, []byte("other"), Claims{Scope: "upload"}), wantErr: ErrUnauthorized},
+		{name: "AlgNone", token: token(key, `{"alg":"none"}`, `{"scope":"upload"}`), wantErr: ErrUnauthorized},
+		{name: "Malformed", token: "token", wantErr: ErrUnauthorized},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := a.HandleBearerAuth(context.Background(), oas.UploadFileOperation, oas.BearerAuth{Token: tt.token})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if errors.Is(err, ErrForbidden) && errors.Is(err, ErrUnauthorized) {
+					t.Fatalf("error is both forbidden and unauthorized: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, ok := PrincipalFrom(ctx)
+			if !ok || p.Scheme != SchemeJWT || p.Subject != "simon" {
+				t.Fatalf("unexpected principal %+v", p)
+			}
+		})
+	}
+}
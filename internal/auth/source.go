@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ogen-go/ogen/ogenerrors"
+
+	"github.com/go-faster/simon/internal/oas"
+)
+
+// Source implements oas.SecuritySource, skipping schemes without credentials.
+type Source struct {
+	APIKey string
+	Token  string
+	// JWTKey signs new token for each request, instead of Token.
+	JWTKey  []byte
+	Subject string
+	Scope   string
+	TTL     time.Duration
+	// InvalidRatio is fraction of requests with corrupted credentials.
+	InvalidRatio float64
+}
+
+var _ oas.SecuritySource = (*Source)(nil)
+
+func (s *Source) corrupt(v string) string {
+	if s.InvalidRatio > 0 && rand.Float64() < s.InvalidRatio { // #nosec G404
+		return v + "invalid"
+	}
+	return v
+}
+
+// ApiKey implements oas.SecuritySource.
+func (s *Source) ApiKey(context.Context, oas.OperationName) (oas.ApiKey, error) {
+	if s.APIKey == "" {
+		return oas.ApiKey{}, ogenerrors.ErrSkipClientSecurity
+	}
+	return oas.ApiKey{APIKey: s.corrupt(s.APIKey)}, nil
+}
+
+// BearerAuth implements oas.SecuritySource.
+func (s *Source) BearerAuth(context.Context, oas.OperationName) (oas.BearerAuth, error) {
+	token := s.Token
+	if len(s.JWTKey) > 0 {
+		now := time.Now()
+		ttl := s.TTL
+		if ttl == 0 {
+			ttl = time.Minute
+		}
+		var err error
+		if token, err = Sign(s.JWTKey, Claims{
+			Subject:   s.Subject,
+			Scope:     s.Scope,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ttl).Unix(),
+		}); err != nil {
+			return oas.BearerAuth{}, err
+		}
+	}
+	if token == "" {
+		return oas.BearerAuth{}, ogenerrors.ErrSkipClientSecurity
+	}
+	return oas.BearerAuth{Token: s.corrupt(token)}, nil
+}
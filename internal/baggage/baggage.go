@@ -0,0 +1,242 @@
+// Package baggage sets W3C baggage on client requests and applies received
+// baggage to telemetry and behavior of server.
+package baggage
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"go.opentelemetry.io/otel/attribute"
+	otelbaggage "go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-faster/simon/internal/middleware"
+	"github.com/go-faster/simon/internal/oas"
+)
+
+// Fault baggage members.
+const (
+	// FaultKey selects fault injected by server.
+	FaultKey = "simon.fault"
+	// FaultLatencyKey overrides delay of FaultLatency.
+	FaultLatencyKey = "simon.fault.latency"
+)
+
+// Faults, values of FaultKey.
+const (
+	FaultLatency = "latency"
+	FaultError   = "error"
+)
+
+// MaxLatency limits FaultLatencyKey, as baggage is set by clients.
+const MaxLatency = 30 * time.Second
+
+// ErrFault is returned for FaultError.
+var ErrFault = errors.New("fault injected by baggage")
+
+type entry struct {
+	key    string
+	values []string
+}
+
+// Entries are baggage members set by client.
+type Entries []entry
+
+// Parse parses comma-separated key=value members, where value may list
+// alternatives separated by "|", chosen randomly for each request:
+//
+//	tenant=acme,tier=free|pro,simon.fault=latency
+func Parse(s string) (Entries, error) {
+	var entries Entries
+	for _, kv := range strings.Split(s, ",") {
+		if kv = strings.TrimSpace(kv); kv == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return nil, errors.Errorf("invalid member %q", kv)
+		}
+		e := entry{key: k, values: strings.Split(v, "|")}
+		for _, value := range e.values {
+			if _, err := otelbaggage.NewMember(k, value); err != nil {
+				return nil, errors.Wrapf(err, "member %q", kv)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Context returns ctx with entries added to its baggage.
+func (e Entries) Context(ctx context.Context) context.Context {
+	if len(e) == 0 {
+		return ctx
+	}
+	b := otelbaggage.FromContext(ctx)
+	for _, v := range e {
+		value := v.values[rand.IntN(len(v.values))] // #nosec G404
+		m, err := otelbaggage.NewMember(v.key, value)
+		if err != nil {
+			// Validated by Parse.
+			continue
+		}
+		if b, err = b.SetMember(m); err != nil {
+			continue
+		}
+	}
+	return otelbaggage.ContextWithBaggage(ctx, b)
+}
+
+// Options of Handler.
+type Options struct {
+	// Keys are members copied to span attributes and log fields,
+	// "*" copies all members.
+	Keys []string
+	// Latency is default delay of FaultLatency.
+	Latency time.Duration
+}
+
+// Handler applies received baggage.
+type Handler struct {
+	opts   Options
+	faults metric.Int64Counter
+}
+
+// New creates new Handler.
+func New(opts Options, meterProvider metric.MeterProvider) (*Handler, error) {
+	if opts.Latency <= 0 {
+		opts.Latency = 500 * time.Millisecond
+	}
+	faults, err := meterProvider.Meter("simon.baggage").Int64Counter("simon.baggage.faults",
+		metric.WithDescription("Number of faults injected by baggage"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "faults")
+	}
+	return &Handler{opts: opts, faults: faults}, nil
+}
+
+func (h *Handler) selected(key string) bool {
+	return key == FaultKey || slices.Contains(h.opts.Keys, "*") || slices.Contains(h.opts.Keys, key)
+}
+
+// Apply copies selected members of ctx baggage to span attributes
+// and log fields of returned context, injecting fault if requested.
+func (h *Handler) Apply(ctx context.Context) (context.Context, error) {
+	b := otelbaggage.FromContext(ctx)
+	if b.Len() == 0 {
+		return ctx, nil
+	}
+	var (
+		attrs  []attribute.KeyValue
+		fields []zap.Field
+	)
+	for _, m := range b.Members() {
+		if !h.selected(m.Key()) {
+			continue
+		}
+		key := "baggage." + m.Key()
+		attrs = append(attrs, attribute.String(key, m.Value()))
+		fields = append(fields, zap.String(key, m.Value()))
+	}
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attrs...)
+	if len(fields) > 0 {
+		ctx = zctx.With(ctx, fields...)
+	}
+
+	fault := b.Member(FaultKey).Value()
+	if fault == "" {
+		return ctx, nil
+	}
+	// Attribute of unknown fault is not set by client, bounding cardinality.
+	kind := "unknown"
+	if fault == FaultLatency || fault == FaultError {
+		kind = fault
+	}
+	h.faults.Add(ctx, 1, metric.WithAttributes(attribute.String("fault", kind)))
+	switch fault {
+	case FaultLatency:
+		d := h.opts.Latency
+		if v, err := time.ParseDuration(b.Member(FaultLatencyKey).Value()); err == nil && v > 0 {
+			d = min(v, MaxLatency)
+		}
+		span.AddEvent("baggage.fault", trace.WithAttributes(
+			attribute.String("fault", fault),
+			attribute.Float64("latency", d.Seconds()),
+		))
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx, ctx.Err()
+		}
+	case FaultError:
+		span.AddEvent("baggage.fault", trace.WithAttributes(attribute.String("fault", fault)))
+		return ctx, ErrFault
+	default:
+		zctx.From(ctx).Warn("Unknown baggage fault", zap.String("fault", fault))
+	}
+	return ctx, nil
+}
+
+// Middleware applies baggage extracted by otelhttp, responding with 500
+// on FaultError.
+func (h *Handler) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, err := h.Apply(r.Context())
+			if err != nil {
+				body, _ := (&oas.Error{Message: err.Error()}).MarshalJSON()
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write(body)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// UnaryInterceptor is gRPC counterpart of Middleware.
+func (h *Handler) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := h.Apply(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return handler(ctx, req)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// StreamInterceptor is gRPC counterpart of Middleware for streaming calls.
+func (h *Handler) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := h.Apply(ss.Context())
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,205 @@
+// Package broker implements in-memory message broker with topics and
+// consumer groups over HTTP, and instrumented producer and consumer.
+package broker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Message in topic.
+type Message struct {
+	Offset  int64             `json:"offset"`
+	Time    time.Time         `json:"time"`
+	Key     string            `json:"key,omitempty"`
+	Value   []byte            `json:"value,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type topic struct {
+	// messages with offsets in [first, first+len(messages)).
+	messages []Message
+	first    int64
+	// groups is next offset of each consumer group.
+	groups map[string]int64
+	// notify is closed and replaced on new messages.
+	notify chan struct{}
+}
+
+func (t *topic) next() int64 {
+	return t.first + int64(len(t.messages))
+}
+
+// Broker is in-memory message broker.
+//
+// Each consumer group receives every message of topic once, messages
+// are shared between consumers of the same group.
+type Broker struct {
+	retention int
+
+	mux    sync.Mutex
+	topics map[string]*topic
+
+	produced metric.Int64Counter
+	consumed metric.Int64Counter
+}
+
+// New creates new broker keeping up to retention messages per topic.
+func New(retention int, meterProvider metric.MeterProvider) (*Broker, error) {
+	b := &Broker{
+		retention: max(retention, 1),
+		topics:    map[string]*topic{},
+	}
+	meter := meterProvider.Meter("simon.broker")
+	var err error
+	if b.produced, err = meter.Int64Counter("simon.broker.produced",
+		metric.WithDescription("Number of produced messages"),
+	); err != nil {
+		return nil, errors.Wrap(err, "produced")
+	}
+	if b.consumed, err = meter.Int64Counter("simon.broker.consumed",
+		metric.WithDescription("Number of consumed messages"),
+	); err != nil {
+		return nil, errors.Wrap(err, "consumed")
+	}
+	lag, err := meter.Int64ObservableGauge("simon.broker.lag",
+		metric.WithDescription("Number of messages not yet consumed by group"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "lag")
+	}
+	if _, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		for _, s := range b.Stats() {
+			for group, g := range s.Groups {
+				o.ObserveInt64(lag, g.Lag, metric.WithAttributes(
+					attribute.String("topic", s.Topic),
+					attribute.String("group", group),
+				))
+			}
+		}
+		return nil
+	}, lag); err != nil {
+		return nil, errors.Wrap(err, "register callback")
+	}
+	return b, nil
+}
+
+// topic returns topic by name, creating it if needed. Must be called with lock held.
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{
+			groups: map[string]int64{},
+			notify: make(chan struct{}),
+		}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// Produce appends messages to topic, returning their offsets.
+func (b *Broker) Produce(ctx context.Context, name string, messages []Message) []int64 {
+	b.mux.Lock()
+	t := b.topic(name)
+	now := time.Now()
+	offsets := make([]int64, 0, len(messages))
+	for _, m := range messages {
+		m.Offset = t.next()
+		if m.Time.IsZero() {
+			m.Time = now
+		}
+		t.messages = append(t.messages, m)
+		offsets = append(offsets, m.Offset)
+	}
+	if n := len(t.messages) - b.retention; n > 0 {
+		t.messages = append(t.messages[:0:0], t.messages[n:]...)
+		t.first += int64(n)
+	}
+	close(t.notify)
+	t.notify = make(chan struct{})
+	b.mux.Unlock()
+
+	b.produced.Add(ctx, int64(len(messages)), metric.WithAttributes(attribute.String("topic", name)))
+	return offsets
+}
+
+// Fetch claims up to limit messages of topic for group, waiting up to
+// wait for new messages if there are none.
+//
+// New group starts from the oldest retained message.
+func (b *Broker) Fetch(ctx context.Context, name, group string, limit int, wait time.Duration) ([]Message, error) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		b.mux.Lock()
+		t := b.topic(name)
+		offset, ok := t.groups[group]
+		if !ok || offset < t.first {
+			// Messages before retention are lost for group.
+			offset = t.first
+		}
+		start := int(offset - t.first)
+		end := min(start+max(limit, 1), len(t.messages))
+		out := append([]Message(nil), t.messages[start:end]...)
+		t.groups[group] = offset + int64(len(out))
+		notify := t.notify
+		b.mux.Unlock()
+
+		if len(out) > 0 {
+			b.consumed.Add(ctx, int64(len(out)), metric.WithAttributes(
+				attribute.String("topic", name),
+				attribute.String("group", group),
+			))
+			return out, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, nil
+		case <-notify:
+		}
+	}
+}
+
+// GroupStats is stats of consumer group.
+type GroupStats struct {
+	Offset int64 `json:"offset"`
+	Lag    int64 `json:"lag"`
+}
+
+// TopicStats is stats of topic.
+type TopicStats struct {
+	Topic  string                `json:"topic"`
+	First  int64                 `json:"first"`
+	Next   int64                 `json:"next"`
+	Groups map[string]GroupStats `json:"groups"`
+}
+
+// Stats returns stats of all topics.
+func (b *Broker) Stats() []TopicStats {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	out := make([]TopicStats, 0, len(b.topics))
+	for name, t := range b.topics {
+		s := TopicStats{
+			Topic:  name,
+			First:  t.first,
+			Next:   t.next(),
+			Groups: map[string]GroupStats{},
+		}
+		for group, offset := range t.groups {
+			s.Groups[group] = GroupStats{
+				Offset: offset,
+				Lag:    t.next() - max(offset, t.first),
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
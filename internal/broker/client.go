@@ -0,0 +1,216 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	messagingSystem = "simon"
+	// attrConsumerGroup is generic consumer group attribute from newer semantic conventions.
+	attrConsumerGroup = "messaging.consumer.group.name"
+)
+
+// Client is instrumented producer and consumer.
+//
+// Trace context is propagated in message headers, consumer spans start
+// new traces linked to producer spans.
+type Client struct {
+	http    *http.Client
+	baseURL string
+	host    string
+	port    int
+
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	lag        metric.Float64Histogram
+}
+
+// NewClient creates new client of broker at baseURL.
+func NewClient(baseURL string, tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse url")
+	}
+	port, _ := strconv.Atoi(u.Port())
+	lag, err := meterProvider.Meter("simon.broker").Float64Histogram("simon.broker.consumer.lag",
+		metric.WithDescription("Time between message production and its processing"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "lag")
+	}
+	return &Client{
+		http: &http.Client{
+			Timeout: maxWait + 5*time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport,
+				otelhttp.WithTracerProvider(tracerProvider),
+				otelhttp.WithMeterProvider(meterProvider),
+			),
+		},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		host:       u.Hostname(),
+		port:       port,
+		tracer:     tracerProvider.Tracer("simon.broker"),
+		propagator: otel.GetTextMapPropagator(),
+		lag:        lag,
+	}, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var r io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "encode")
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, r)
+	if err != nil {
+		return errors.Wrap(err, "create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req) // #nosec G704
+	if err != nil {
+		return errors.Wrap(err, "do request")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrap(err, "decode")
+	}
+	return nil
+}
+
+// Ping checks broker availability.
+func (c *Client) Ping(ctx context.Context) error {
+	var stats []TopicStats
+	return c.do(ctx, http.MethodGet, "/api/stats", nil, &stats)
+}
+
+// Produce message to topic, returning its offset.
+func (c *Client) Produce(ctx context.Context, topic, key string, value []byte) (int64, error) {
+	ctx, span := c.tracer.Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String(messagingSystem),
+			semconv.MessagingOperationPublish,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingMessageBodySize(len(value)),
+			semconv.ServerAddress(c.host),
+			semconv.ServerPort(c.port),
+		),
+	)
+	defer span.End()
+
+	msg := Message{
+		Key:     key,
+		Value:   value,
+		Headers: map[string]string{},
+	}
+	c.propagator.Inject(ctx, propagation.MapCarrier(msg.Headers))
+
+	var resp ProduceResponse
+	err := c.do(ctx, http.MethodPost, "/topics/"+url.PathEscape(topic)+"/messages",
+		ProduceRequest{Messages: []Message{msg}}, &resp,
+	)
+	if err == nil && len(resp.Offsets) != 1 {
+		err = errors.Errorf("unexpected offsets: %v", resp.Offsets)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+	offset := resp.Offsets[0]
+	span.SetAttributes(semconv.MessagingMessageID(strconv.FormatInt(offset, 10)))
+	return offset, nil
+}
+
+// Handler processes message in context of consumer span.
+type Handler func(ctx context.Context, msg Message) error
+
+// Consume messages of topic as member of group until context is done.
+func (c *Client) Consume(ctx context.Context, topic, group string, handler Handler) error {
+	q := url.Values{}
+	q.Set("group", group)
+	q.Set("limit", "100")
+	q.Set("wait", "5s")
+	path := "/topics/" + url.PathEscape(topic) + "/messages?" + q.Encode()
+	for {
+		var resp FetchResponse
+		if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// Broker may be not ready yet.
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+		for _, msg := range resp.Messages {
+			c.process(ctx, topic, group, msg, handler)
+		}
+	}
+}
+
+func (c *Client) process(ctx context.Context, topic, group string, msg Message, handler Handler) {
+	producer := trace.SpanContextFromContext(
+		c.propagator.Extract(ctx, propagation.MapCarrier(msg.Headers)),
+	)
+	var links []trace.Link
+	if producer.IsValid() {
+		links = append(links, trace.Link{SpanContext: producer})
+	}
+	ctx, span := c.tracer.Start(ctx, topic+" deliver",
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String(messagingSystem),
+			semconv.MessagingOperationDeliver,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingMessageID(strconv.FormatInt(msg.Offset, 10)),
+			semconv.MessagingMessageBodySize(len(msg.Value)),
+			attribute.String(attrConsumerGroup, group),
+			semconv.ServerAddress(c.host),
+			semconv.ServerPort(c.port),
+		),
+	)
+	defer span.End()
+
+	c.lag.Record(ctx, time.Since(msg.Time).Seconds(), metric.WithAttributes(
+		attribute.String("topic", topic),
+		attribute.String("group", group),
+	))
+	if err := handler(ctx, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
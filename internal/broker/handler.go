@@ -0,0 +1,85 @@
+package broker
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ProduceRequest is body of produce request.
+type ProduceRequest struct {
+	Messages []Message `json:"messages"`
+}
+
+// ProduceResponse is body of produce response.
+type ProduceResponse struct {
+	Offsets []int64 `json:"offsets"`
+}
+
+// FetchResponse is body of fetch response.
+type FetchResponse struct {
+	Messages []Message `json:"messages"`
+}
+
+const maxWait = 30 * time.Second
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Handler returns HTTP API of broker:
+//
+//	POST /topics/{topic}/messages                        produce
+//	GET  /topics/{topic}/messages?group=&limit=&wait=    fetch
+//	GET  /api/stats                                      topics and lag
+func (b *Broker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /topics/{topic}/messages", func(w http.ResponseWriter, r *http.Request) {
+		var req ProduceRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16*1024*1024)).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, ProduceResponse{
+			Offsets: b.Produce(r.Context(), r.PathValue("topic"), req.Messages),
+		})
+	})
+	mux.HandleFunc("GET /topics/{topic}/messages", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		group := q.Get("group")
+		if group == "" {
+			http.Error(w, "group is required", http.StatusBadRequest)
+			return
+		}
+		limit := 100
+		if v := q.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, "bad limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		var wait time.Duration
+		if v := q.Get("wait"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				http.Error(w, "bad wait", http.StatusBadRequest)
+				return
+			}
+			wait = min(d, maxWait)
+		}
+		messages, err := b.Fetch(r.Context(), r.PathValue("topic"), group, limit, wait)
+		if err != nil {
+			// Client is gone.
+			return
+		}
+		writeJSON(w, FetchResponse{Messages: messages})
+	})
+	mux.HandleFunc("GET /api/stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, b.Stats())
+	})
+	return mux
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/go-faster/errors"
+	ohttp "github.com/ogen-go/ogen/http"
+
+	"github.com/go-faster/simon/internal/oas"
+	"github.com/go-faster/simon/internal/simonpb"
+)
+
+// Protocols of server API.
+const (
+	protocolHTTP = "http"
+	protocolGRPC = "grpc"
+)
+
+// uploadChunkSize is size of gRPC upload chunk.
+const uploadChunkSize = 64 * 1024
+
+// apiClient calls server API over HTTP or gRPC.
+type apiClient struct {
+	http *oas.Client
+	grpc simonpb.SimonServiceClient
+}
+
+// Status returns server status message.
+func (c *apiClient) Status(ctx context.Context, protocol string) (string, error) {
+	if protocol == protocolGRPC {
+		resp, err := c.grpc.Status(ctx, &simonpb.StatusRequest{})
+		if err != nil {
+			return "", err
+		}
+		return resp.GetMessage(), nil
+	}
+	resp, err := c.http.Status(ctx)
+	if err != nil {
+		return "", err
+	}
+	return resp.Message, nil
+}
+
+// UploadFile uploads payload and returns its hash.
+func (c *apiClient) UploadFile(ctx context.Context, protocol string, payload []byte, iterations int) (string, error) {
+	if protocol == protocolGRPC {
+		stream, err := c.grpc.UploadFile(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "open stream")
+		}
+		for i := 0; i == 0 || i < len(payload); i += uploadChunkSize {
+			req := &simonpb.UploadFileRequest{
+				Chunk: payload[i:min(i+uploadChunkSize, len(payload))],
+			}
+			if i == 0 {
+				req.Iterations = int32(min(iterations, 1<<31-1)) // #nosec G115
+			}
+			if err := stream.Send(req); err == io.EOF {
+				// Stream is aborted by server, status is returned by CloseAndRecv.
+				break
+			} else if err != nil {
+				return "", errors.Wrap(err, "send")
+			}
+		}
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			return "", err
+		}
+		return resp.GetHash(), nil
+	}
+	resp, err := c.http.UploadFile(ctx, &oas.UploadFileReq{
+		File: ohttp.MultipartFile{
+			Name: "random.bin",
+			Size: int64(len(payload)),
+			File: bytes.NewReader(payload),
+		},
+		Iterations: oas.NewOptInt(iterations),
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Hash, nil
+}
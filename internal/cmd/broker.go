@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-faster/errors"
+	sdka "github.com/go-faster/sdk/app"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/go-faster/simon/internal/broker"
+	"github.com/go-faster/simon/internal/middleware"
+)
+
+// brokerSpanName names server spans by route pattern of h, e.g.
+// "POST /topics/{topic}/messages", keeping topics out of span names.
+func brokerSpanName(h http.Handler) func(string, *http.Request) string {
+	return func(_ string, r *http.Request) string {
+		if mux, ok := h.(*http.ServeMux); ok {
+			if _, pattern := mux.Handler(r); pattern != "" {
+				return pattern
+			}
+		}
+		return r.Method
+	}
+}
+
+func cmdBroker() *cobra.Command {
+	var arg struct {
+		Addr      string
+		Retention int
+	}
+	cmd := &cobra.Command{
+		Use:   "broker",
+		Short: "Run in-memory message broker",
+		Run: func(cmd *cobra.Command, args []string) {
+			sdka.Run(func(ctx context.Context, lg *zap.Logger, t *sdka.Telemetry) error {
+				b, err := broker.New(arg.Retention, t.MeterProvider())
+				if err != nil {
+					return errors.Wrap(err, "broker")
+				}
+				h := b.Handler()
+				s := &http.Server{
+					Addr:              arg.Addr,
+					ReadHeaderTimeout: time.Second,
+					Handler: middleware.Chain{
+						otelhttp.NewMiddleware("",
+							otelhttp.WithSpanNameFormatter(brokerSpanName(h)),
+							otelhttp.WithMeterProvider(t.MeterProvider()),
+							otelhttp.WithTracerProvider(t.TracerProvider()),
+						),
+						middleware.RequestID(),
+						middleware.Log(),
+						middleware.Recover(),
+					}.Then(h),
+					BaseContext: func(listener net.Listener) context.Context {
+						return t.BaseContext()
+					},
+				}
+
+				lg.Info("Starting broker", zap.String("addr", arg.Addr))
+
+				g, ctx := errgroup.WithContext(ctx)
+				g.Go(func() error {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-t.ShutdownContext().Done():
+						return s.Shutdown(t.BaseContext())
+					}
+				})
+				g.Go(func() error {
+					if err := s.ListenAndServe(); err != nil {
+						if errors.Is(err, http.ErrServerClosed) {
+							lg.Info("HTTP server closed gracefully")
+							return nil
+						}
+						return errors.Wrap(err, "http server")
+					}
+					return nil
+				})
+				return g.Wait()
+			},
+				sdka.WithServiceName("simon.broker"),
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&arg.Addr, "addr", "localhost:8090", "Listen address")
+	cmd.Flags().IntVar(&arg.Retention, "retention", 100_000, "Number of messages kept per topic")
+
+	return cmd
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"net"
+
+	"github.com/go-faster/errors"
+	sdka "github.com/go-faster/sdk/app"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/simon/internal/resp"
+)
+
+func cmdCache() *cobra.Command {
+	var arg struct {
+		Addr string
+		resp.Options
+	}
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Run in-memory RESP (Redis protocol) server",
+		Run: func(cmd *cobra.Command, args []string) {
+			sdka.Run(func(ctx context.Context, lg *zap.Logger, t *sdka.Telemetry) error {
+				ln, err := net.Listen("tcp", arg.Addr)
+				if err != nil {
+					return errors.Wrap(err, "listen")
+				}
+				lg.Info("Starting RESP server", zap.String("addr", arg.Addr))
+
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				stop := context.AfterFunc(t.ShutdownContext(), cancel)
+				defer stop()
+
+				return resp.NewServer(arg.Options).Serve(ctx, ln)
+			},
+				sdka.WithServiceName("simon.cache"),
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&arg.Addr, "addr", "localhost:6379", "Listen address")
+	cmd.Flags().DurationVar(&arg.Latency, "latency", 0, "Latency added to every command")
+	cmd.Flags().DurationVar(&arg.Jitter, "jitter", 0, "Random additional latency")
+	cmd.Flags().Float64Var(&arg.MissRatio, "miss-ratio", 0, "Probability of GET miss for existing key")
+
+	return cmd
+}
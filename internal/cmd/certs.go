@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/go-faster/errors"
+	sdka "github.com/go-faster/sdk/app"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/simon/internal/tlsconfig"
+)
+
+func cmdCerts() *cobra.Command {
+	var arg struct {
+		Dir   string
+		Hosts []string
+	}
+	cmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Generate CA, server and client certificates for TLS and mTLS",
+		Run: func(cmd *cobra.Command, args []string) {
+			sdka.Run(func(ctx context.Context, lg *zap.Logger, t *sdka.Telemetry) error {
+				ca, err := tlsconfig.GenerateCA("simon")
+				if err != nil {
+					return errors.Wrap(err, "ca")
+				}
+				server, err := tlsconfig.Issue(ca, "simon.server", arg.Hosts, false)
+				if err != nil {
+					return errors.Wrap(err, "server")
+				}
+				client, err := tlsconfig.Issue(ca, "simon.client", nil, true)
+				if err != nil {
+					return errors.Wrap(err, "client")
+				}
+				if err := os.MkdirAll(arg.Dir, 0o750); err != nil {
+					return errors.Wrap(err, "mkdir")
+				}
+				for name, pair := range map[string]tlsconfig.Pair{
+					"ca":     ca,
+					"server": server,
+					"client": client,
+				} {
+					for ext, data := range map[string][]byte{
+						".crt": pair.Cert,
+						".key": pair.Key,
+					} {
+						out := filepath.Join(arg.Dir, name+ext)
+						if err := os.WriteFile(out, data, 0o600); err != nil {
+							return errors.Wrap(err, "write")
+						}
+						lg.Info("Wrote", zap.String("file", out))
+					}
+				}
+				return nil
+			},
+				sdka.WithServiceName("simon.certs"),
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&arg.Dir, "dir", "certs", "Output directory")
+	cmd.Flags().StringSliceVar(&arg.Hosts, "hosts", []string{"localhost", "127.0.0.1"}, "Server certificate DNS names and IPs")
+
+	return cmd
+}
@@ -1,52 +1,107 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/go-faster/errors"
 	sdka "github.com/go-faster/sdk/app"
 	"github.com/go-faster/sdk/zctx"
-	ohttp "github.com/ogen-go/ogen/http"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/go-faster/simon/internal/app"
+	"github.com/go-faster/simon/internal/auth"
+	"github.com/go-faster/simon/internal/baggage"
+	"github.com/go-faster/simon/internal/broker"
+	"github.com/go-faster/simon/internal/conntrace"
+	"github.com/go-faster/simon/internal/content"
 	"github.com/go-faster/simon/internal/oas"
+	"github.com/go-faster/simon/internal/simonpb"
+	"github.com/go-faster/simon/internal/slow"
+	"github.com/go-faster/simon/internal/tlsconfig"
 )
 
 func cmdClient() *cobra.Command {
 	var arg struct {
 		UploadRPS            int
 		UploadHashIterations int
+		UploadPayloads       int
+		BrokerAddr           string
+		BrokerTopic          string
+		BrokerRPS            float64
+		StatusProtocol       string
+		UploadProtocol       string
+		Auth                 auth.Source
+		JWTKey               string
+		TLS                  tlsconfig.ClientOptions
+		GRPCTLS              bool
+		Transport            transportOptions
+		Timeout              time.Duration
+		Slowloris            slow.SlowlorisOptions
+		Download             downloadOptions
+		Baggage              string
 	}
 	cmd := &cobra.Command{
 		Use:   "client",
 		Short: "Run a HTTP client",
 		Run: func(cmd *cobra.Command, args []string) {
 			sdka.Run(func(ctx context.Context, logger *zap.Logger, t *sdka.Telemetry) error {
+				for _, p := range []string{arg.StatusProtocol, arg.UploadProtocol} {
+					if p != protocolHTTP && p != protocolGRPC {
+						return errors.Errorf("unknown protocol %q", p)
+					}
+				}
+				bag, err := baggage.Parse(arg.Baggage)
+				if err != nil {
+					return errors.Wrap(err, "parse baggage")
+				}
 				addr := os.Getenv("SERVER_ADDR")
 				if addr == "" {
 					addr = "http://localhost:8080"
 				}
 				spanNameFormatter := app.NewSpanNameFormatter(&oas.Server{})
-				c, err := oas.NewClient(addr,
+				tlsConfig, err := tlsconfig.Client(arg.TLS)
+				if err != nil {
+					return errors.Wrap(err, "tls")
+				}
+				tlsMetrics, err := tlsconfig.NewMetrics(t.TracerProvider(), t.MeterProvider())
+				if err != nil {
+					return errors.Wrap(err, "tls metrics")
+				}
+				transport, err := newTransport(arg.Transport, tlsConfig)
+				if err != nil {
+					return errors.Wrap(err, "transport")
+				}
+				if arg.Transport.Trace {
+					connMetrics, err := conntrace.NewMetrics(t.MeterProvider())
+					if err != nil {
+						return errors.Wrap(err, "conntrace")
+					}
+					transport = connMetrics.Transport(transport)
+				}
+				arg.Auth.JWTKey = []byte(arg.JWTKey)
+				c, err := oas.NewClient(addr, &arg.Auth,
 					oas.WithMeterProvider(t.MeterProvider()),
 					oas.WithTracerProvider(t.TracerProvider()),
 					oas.WithClient(&http.Client{
-						Timeout: time.Second * 2,
-						Transport: otelhttp.NewTransport(http.DefaultTransport,
+						Timeout: arg.Timeout,
+						Transport: otelhttp.NewTransport(tlsMetrics.Transport(transport),
 							otelhttp.WithSpanNameFormatter(spanNameFormatter),
 							otelhttp.WithMeterProvider(t.MeterProvider()),
 							otelhttp.WithTracerProvider(t.TracerProvider()),
@@ -56,7 +111,32 @@ func cmdClient() *cobra.Command {
 				if err != nil {
 					return errors.Wrap(err, "client")
 				}
+				api := &apiClient{http: c}
+				if arg.StatusProtocol == protocolGRPC || arg.UploadProtocol == protocolGRPC {
+					creds := insecure.NewCredentials()
+					if arg.GRPCTLS {
+						creds = credentials.NewTLS(tlsConfig)
+					}
+					conn, err := grpc.NewClient(getEnvDefault("SERVER_GRPC_ADDR", "localhost:8081"),
+						grpc.WithTransportCredentials(creds),
+						grpc.WithPerRPCCredentials(&arg.Auth),
+						grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+							otelgrpc.WithMeterProvider(t.MeterProvider()),
+							otelgrpc.WithTracerProvider(t.TracerProvider()),
+						)),
+					)
+					if err != nil {
+						return errors.Wrap(err, "grpc client")
+					}
+					defer func() {
+						_ = conn.Close()
+					}()
+					api.grpc = simonpb.NewSimonServiceClient(conn)
+				}
 				g, ctx := errgroup.WithContext(ctx)
+				if err := runWorkloads(ctx, g, logger, t); err != nil {
+					return err
+				}
 				g.Go(func() error {
 					ticker := time.NewTicker(time.Second)
 					tracer := t.TracerProvider().Tracer("")
@@ -64,18 +144,19 @@ func cmdClient() *cobra.Command {
 						ctx, cancel := context.WithTimeout(ctx, time.Millisecond*250)
 						defer cancel()
 
+						ctx = bag.Context(ctx)
 						ctx, span := tracer.Start(ctx, "client.tick")
 						defer span.End()
 
 						lg := zctx.From(ctx)
 						lg.Info("Sending request")
 
-						status, err := c.Status(ctx)
+						message, err := api.Status(ctx, arg.StatusProtocol)
 						if err != nil {
 							lg.Error("Request failed", zap.Error(err))
 							return
 						}
-						lg.Info("Request succeeded", zap.String("message", status.Message))
+						lg.Info("Request succeeded", zap.String("message", message))
 					}
 					tick()
 					for {
@@ -94,6 +175,8 @@ func cmdClient() *cobra.Command {
 					const burst = 1
 					limiter := rate.NewLimiter(rate.Limit(arg.UploadRPS), burst)
 					rnd := rand.New(rand.NewSource(10)) // #nosec G404
+					// Reused payloads, so server cache can hit.
+					payloads := make([][]byte, max(arg.UploadPayloads, 0))
 
 					tracer := t.TracerProvider().Tracer("")
 
@@ -105,10 +188,12 @@ func cmdClient() *cobra.Command {
 						ctx, cancel := context.WithTimeout(ctx, time.Second*5)
 						defer cancel()
 
+						ctx = bag.Context(ctx)
 						ctx, span := tracer.Start(ctx, "client.upload",
 							trace.WithAttributes(
 								attribute.Int("rps", arg.UploadRPS),
 								attribute.Int("hash_iterations", arg.UploadHashIterations),
+								attribute.String("protocol", arg.UploadProtocol),
 							),
 						)
 						defer span.End()
@@ -116,26 +201,31 @@ func cmdClient() *cobra.Command {
 						lg := zctx.From(ctx)
 						lg.Info("Uploading data")
 
-						// Generate payload.
+						// Generate payload or pick reused one.
 						const payloadSize = 1024 * 1024 * 1 // 1MB
-						payload := make([]byte, payloadSize)
-						if _, err := rnd.Read(payload); err != nil {
-							return errors.Wrap(err, "gen payload")
+						idx := -1
+						var payload []byte
+						if len(payloads) > 0 {
+							idx = rnd.Intn(len(payloads))
+							payload = payloads[idx]
+							span.SetAttributes(attribute.Int("payload", idx))
+						}
+						if payload == nil {
+							payload = make([]byte, payloadSize)
+							if _, err := rnd.Read(payload); err != nil {
+								return errors.Wrap(err, "gen payload")
+							}
+							if idx >= 0 {
+								payloads[idx] = payload
+							}
 						}
 
-						msg, err := c.UploadFile(ctx, &oas.UploadFileReq{
-							File: ohttp.MultipartFile{
-								Name: "random.bin",
-								Size: int64(len(payload)),
-								File: bytes.NewReader(payload),
-							},
-							Iterations: oas.NewOptInt(arg.UploadHashIterations),
-						})
+						hash, err := api.UploadFile(ctx, arg.UploadProtocol, payload, arg.UploadHashIterations)
 						if err != nil {
 							return errors.Wrap(err, "upload file")
 						}
 
-						lg.Info("Upload succeeded", zap.String("hash", msg.Hash))
+						lg.Info("Upload succeeded", zap.String("hash", hash))
 						// Verifying hash.
 						h := sha256.New()
 						for i := 0; i < arg.UploadHashIterations; i++ {
@@ -147,8 +237,8 @@ func cmdClient() *cobra.Command {
 						span.AddEvent("Hash verification",
 							trace.WithAttributes(
 								attribute.String("expected", gotHash),
-								attribute.String("got", msg.Hash),
-								attribute.Bool("equal", gotHash == msg.Hash),
+								attribute.String("got", hash),
+								attribute.Bool("equal", gotHash == hash),
 							),
 						)
 
@@ -171,6 +261,78 @@ func cmdClient() *cobra.Command {
 						}
 					}
 				})
+				if arg.Download.RPS > 0 {
+					d, err := newDownloader(c, arg.Download, bag, t.TracerProvider(), t.MeterProvider())
+					if err != nil {
+						return errors.Wrap(err, "downloader")
+					}
+					ctx, cancel := context.WithCancel(ctx)
+					stop := context.AfterFunc(t.ShutdownContext(), cancel)
+					g.Go(func() error {
+						defer cancel()
+						defer stop()
+						if err := d.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+							return errors.Wrap(err, "download")
+						}
+						return nil
+					})
+				}
+				if arg.Slowloris.Conns > 0 {
+					arg.Slowloris.URL = addr
+					arg.Slowloris.TLS = tlsConfig
+					sl, err := slow.NewSlowloris(arg.Slowloris, t.TracerProvider(), t.MeterProvider())
+					if err != nil {
+						return errors.Wrap(err, "slowloris")
+					}
+					ctx, cancel := context.WithCancel(ctx)
+					stop := context.AfterFunc(t.ShutdownContext(), cancel)
+					g.Go(func() error {
+						defer cancel()
+						defer stop()
+						if err := sl.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+							return errors.Wrap(err, "slowloris")
+						}
+						return nil
+					})
+				}
+				if arg.BrokerAddr != "" {
+					b, err := broker.NewClient(arg.BrokerAddr, t.TracerProvider(), t.MeterProvider())
+					if err != nil {
+						return errors.Wrap(err, "broker")
+					}
+					g.Go(func() error {
+						// Messages.
+						limiter := rate.NewLimiter(rate.Limit(arg.BrokerRPS), 1)
+						tracer := t.TracerProvider().Tracer("")
+						lg := zctx.From(ctx)
+						produce := func(seq int) {
+							ctx, span := tracer.Start(bag.Context(ctx), "client.produce")
+							defer span.End()
+
+							key := strconv.Itoa(seq)
+							value := fmt.Sprintf(`{"seq":%d,"time":%q}`, seq, time.Now().Format(time.RFC3339Nano))
+							offset, err := b.Produce(ctx, arg.BrokerTopic, key, []byte(value))
+							if err != nil {
+								lg.Error("Produce failed", zap.Error(err))
+								return
+							}
+							zctx.From(ctx).Debug("Produced message", zap.Int64("offset", offset))
+						}
+						for seq := 0; ; seq++ {
+							select {
+							case <-t.ShutdownContext().Done():
+								return nil
+							case <-ctx.Done():
+								return ctx.Err()
+							default:
+							}
+							if err := limiter.Wait(ctx); err != nil {
+								return errors.Wrap(err, "limiter")
+							}
+							produce(seq)
+						}
+					})
+				}
 				return g.Wait()
 			},
 				sdka.WithServiceName("simon.client"),
@@ -180,6 +342,46 @@ func cmdClient() *cobra.Command {
 
 	cmd.Flags().IntVar(&arg.UploadRPS, "upload-rps", 1, "Upload requests per second")
 	cmd.Flags().IntVar(&arg.UploadHashIterations, "upload-hash-iterations", 3, "Upload hash iterations")
+	cmd.Flags().IntVar(&arg.UploadPayloads, "upload-payloads", 0, "Number of distinct upload payloads reused randomly, zero generates new payload for every upload")
+	cmd.Flags().StringVar(&arg.StatusProtocol, "status-protocol", protocolHTTP, "Protocol of status requests (http, grpc)")
+	cmd.Flags().StringVar(&arg.UploadProtocol, "upload-protocol", protocolHTTP, "Protocol of uploads (http, grpc)")
+	cmd.Flags().StringVar(&arg.Auth.APIKey, "api-key", os.Getenv("AUTH_API_KEY"), "API key of uploads")
+	cmd.Flags().StringVar(&arg.Auth.Token, "bearer-token", os.Getenv("AUTH_BEARER_TOKEN"), "Bearer token of uploads")
+	cmd.Flags().StringVar(&arg.JWTKey, "jwt-key", os.Getenv("AUTH_JWT_KEY"), "HS256 key to sign bearer tokens of uploads, overrides --bearer-token")
+	cmd.Flags().StringVar(&arg.Auth.Subject, "jwt-subject", "simon.client", "Subject of signed tokens")
+	cmd.Flags().StringVar(&arg.Auth.Scope, "jwt-scope", "upload", "Scope of signed tokens")
+	cmd.Flags().DurationVar(&arg.Auth.TTL, "jwt-ttl", time.Minute, "Lifetime of signed tokens")
+	cmd.Flags().Float64Var(&arg.Auth.InvalidRatio, "auth-invalid-ratio", 0, "Fraction of uploads with corrupted credentials")
+	cmd.Flags().StringVar(&arg.TLS.CAFile, "tls-ca-file", os.Getenv("TLS_CA_FILE"), "CA certificate to verify server")
+	cmd.Flags().StringVar(&arg.TLS.CertFile, "tls-cert-file", os.Getenv("TLS_CERT_FILE"), "Client certificate for mTLS")
+	cmd.Flags().StringVar(&arg.TLS.KeyFile, "tls-key-file", os.Getenv("TLS_KEY_FILE"), "Client key for mTLS")
+	cmd.Flags().StringVar(&arg.TLS.ServerName, "tls-server-name", "", "Server name to verify, host of address by default")
+	cmd.Flags().BoolVar(&arg.TLS.Insecure, "tls-insecure", false, "Skip server certificate verification, e.g. for self-signed")
+	cmd.Flags().BoolVar(&arg.GRPCTLS, "grpc-tls", false, "Use TLS for gRPC, HTTPS is enabled by SERVER_ADDR scheme")
+	cmd.Flags().StringVar(&arg.Transport.Protocol, "http-protocol", httpProtocol1, "HTTP protocol (http1, http1-close, http2)")
+	cmd.Flags().IntVar(&arg.Transport.MaxStreams, "http2-max-streams", 0, "Max concurrent HTTP/2 requests, zero is server limit")
+	cmd.Flags().DurationVar(&arg.Timeout, "http-timeout", 2*time.Second, "HTTP request timeout")
+	cmd.Flags().DurationVar(&arg.Transport.DialTimeout, "http-dial-timeout", 30*time.Second, "HTTP dial timeout")
+	cmd.Flags().IntVar(&arg.Transport.MaxIdleConns, "http-max-idle-conns", 100, "Max idle HTTP connections, zero is no limit")
+	cmd.Flags().IntVar(&arg.Transport.MaxIdleConnsPerHost, "http-max-idle-conns-per-host", http.DefaultMaxIdleConnsPerHost, "Max idle HTTP connections per host")
+	cmd.Flags().DurationVar(&arg.Transport.IdleConnTimeout, "http-idle-conn-timeout", 90*time.Second, "Idle HTTP connection timeout, zero is no limit")
+	cmd.Flags().BoolVar(&arg.Transport.Trace, "http-trace", false, "Trace connection lifecycle (DNS, connect, TLS, first byte) as span events and histograms")
+	cmd.Flags().IntVar(&arg.Transport.Slow.Upload, "upload-bandwidth", 0, "Upload request body bytes per second, zero is no limit")
+	cmd.Flags().IntVar(&arg.Transport.Slow.Download, "read-bandwidth", 0, "Response body read bytes per second, zero is no limit")
+	cmd.Flags().IntVar(&arg.Transport.Slow.Chunk, "bandwidth-chunk", 0, "Max bytes sent or read at once with limited bandwidth, one second of bandwidth by default")
+	cmd.Flags().IntVar(&arg.Slowloris.Conns, "slowloris-conns", 0, "Connections sending request headers slowly, never finishing them")
+	cmd.Flags().DurationVar(&arg.Slowloris.Interval, "slowloris-interval", time.Second, "Interval between slowloris header lines")
+	cmd.Flags().Float64Var(&arg.Download.RPS, "download-rps", 0, "Download requests per second, zero disables downloads")
+	cmd.Flags().Int64Var(&arg.Download.Size, "download-size", 1<<20, "Download size in bytes")
+	cmd.Flags().StringVar(&arg.Download.Type, "download-type", string(content.Random), "Download content type (random, text, json)")
+	cmd.Flags().StringVar(&arg.Download.Encoding, "download-encoding", "identity", "Download content encoding (identity, gzip, zstd)")
+	cmd.Flags().IntVar(&arg.Download.ChunkSize, "download-chunk-size", 0, "Bytes written by server before flush, zero is no flushes")
+	cmd.Flags().IntVar(&arg.Download.ChunkDelay, "download-chunk-delay", 0, "Delay between chunks in milliseconds")
+	cmd.Flags().Float64Var(&arg.Download.RangeRatio, "download-range-ratio", 0, "Fraction of downloads of random byte range")
+	cmd.Flags().StringVar(&arg.Baggage, "baggage", os.Getenv("BAGGAGE"), "Baggage members of requests, e.g. tenant=acme,tier=free|pro with random alternative")
+	cmd.Flags().StringVar(&arg.BrokerAddr, "broker-addr", os.Getenv("BROKER_ADDR"), "Broker URL, enables producing messages")
+	cmd.Flags().StringVar(&arg.BrokerTopic, "broker-topic", "simon.events", "Broker topic")
+	cmd.Flags().Float64Var(&arg.BrokerRPS, "broker-rps", 1, "Produced messages per second")
 
 	return cmd
 }
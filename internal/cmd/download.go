@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"io"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/go-faster/simon/internal/baggage"
+	"github.com/go-faster/simon/internal/content"
+	"github.com/go-faster/simon/internal/oas"
+)
+
+type downloadOptions struct {
+	RPS        float64
+	Size       int64
+	Type       string
+	Encoding   string
+	ChunkSize  int
+	ChunkDelay int
+	// RangeRatio is fraction of requests of random byte range.
+	RangeRatio float64
+}
+
+// downloader downloads generated content, verifying checksum against
+// content generated locally from the same seed.
+type downloader struct {
+	client     *oas.Client
+	opts       downloadOptions
+	baggage    baggage.Entries
+	tracer     trace.Tracer
+	mismatches metric.Int64Counter
+}
+
+func newDownloader(client *oas.Client, opts downloadOptions, bag baggage.Entries, tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*downloader, error) {
+	var kind oas.ContentKind
+	if err := kind.UnmarshalText([]byte(opts.Type)); err != nil {
+		return nil, errors.Wrap(err, "type")
+	}
+	var encoding oas.DownloadEncoding
+	if err := encoding.UnmarshalText([]byte(opts.Encoding)); err != nil {
+		return nil, errors.Wrap(err, "encoding")
+	}
+	mismatches, err := meterProvider.Meter("simon.client").Int64Counter("simon.client.download.mismatches",
+		metric.WithDescription("Number of downloads with unexpected checksum"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "mismatches")
+	}
+	return &downloader{
+		client:     client,
+		opts:       opts,
+		baggage:    bag,
+		tracer:     tracerProvider.Tracer(""),
+		mismatches: mismatches,
+	}, nil
+}
+
+// decode returns decoded body, which must be closed to release decoder.
+func decode(r io.Reader, encoding string) (io.ReadCloser, error) {
+	switch encoding {
+	case "", "identity":
+		return io.NopCloser(r), nil
+	case "gzip":
+		return gzip.NewReader(r)
+	case "zstd":
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	default:
+		return nil, errors.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+func (d *downloader) download(ctx context.Context) error {
+	var (
+		kind   = content.Kind(d.opts.Type)
+		seed   = rand.Int64() // #nosec G404
+		start  = int64(0)
+		length = d.opts.Size
+	)
+	params := oas.DownloadParams{
+		Size:       oas.NewOptInt64(d.opts.Size),
+		Type:       oas.NewOptContentKind(oas.ContentKind(d.opts.Type)),
+		Seed:       oas.NewOptInt64(seed),
+		ChunkSize:  oas.NewOptInt(d.opts.ChunkSize),
+		ChunkDelay: oas.NewOptInt(d.opts.ChunkDelay),
+		Encoding:   oas.NewOptDownloadEncoding(oas.DownloadEncoding(d.opts.Encoding)),
+	}
+	if d.opts.Size > 0 && rand.Float64() < d.opts.RangeRatio { // #nosec G404
+		start = rand.Int64N(d.opts.Size)            // #nosec G404
+		length = 1 + rand.Int64N(d.opts.Size-start) // #nosec G404
+		params.Range.SetTo("bytes=" + strconv.FormatInt(start, 10) + "-" + strconv.FormatInt(start+length-1, 10))
+	}
+
+	ctx, span := d.tracer.Start(d.baggage.Context(ctx), "client.download",
+		trace.WithAttributes(
+			attribute.String("type", d.opts.Type),
+			attribute.Int64("size", d.opts.Size),
+			attribute.Int64("seed", seed),
+			attribute.String("encoding", d.opts.Encoding),
+			attribute.String("range", params.Range.Or("")),
+		),
+	)
+	defer span.End()
+
+	// Generated client reads whole body before returning, so chunks are
+	// not observed as they arrive and memory use grows with size.
+	res, err := d.client.Download(ctx, params)
+	if err != nil {
+		return errors.Wrap(err, "download")
+	}
+	var headers *oas.DownloadHeaders
+	switch res := res.(type) {
+	case *oas.DownloadOK:
+		headers = (*oas.DownloadHeaders)(res)
+		start, length = 0, d.opts.Size
+	case *oas.DownloadPartialContent:
+		headers = (*oas.DownloadHeaders)(res)
+	default:
+		return errors.Errorf("unexpected response %T", res)
+	}
+	body, err := decode(headers.Response, headers.ContentEncoding.Or(""))
+	if err != nil {
+		return errors.Wrap(err, "decode")
+	}
+	defer func() { _ = body.Close() }()
+	got := sha256.New()
+	n, err := io.Copy(got, body)
+	if err != nil {
+		return errors.Wrap(err, "read")
+	}
+
+	expected := sha256.New()
+	r, err := content.Range(kind, seed, d.opts.Size, start, length)
+	if err != nil {
+		return errors.Wrap(err, "generate")
+	}
+	if _, err := io.Copy(expected, r); err != nil {
+		return errors.Wrap(err, "generate")
+	}
+	equal := bytes.Equal(got.Sum(nil), expected.Sum(nil))
+	span.AddEvent("Checksum verification",
+		trace.WithAttributes(
+			attribute.Int64("bytes", n),
+			attribute.Bool("equal", equal),
+		),
+	)
+	if !equal {
+		d.mismatches.Add(ctx, 1)
+		span.SetStatus(codes.Error, "checksum mismatch")
+		return errors.Errorf("checksum mismatch of %d bytes", n)
+	}
+	return nil
+}
+
+// Run downloads with configured rate until ctx is done.
+func (d *downloader) Run(ctx context.Context) error {
+	limiter := rate.NewLimiter(rate.Limit(d.opts.RPS), 1)
+	lg := zctx.From(ctx)
+	for {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		if err := d.download(ctx); err != nil {
+			lg.Error("Download failed", zap.Error(err))
+		} else {
+			lg.Debug("Download succeeded")
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/go-faster/errors"
+	sdka "github.com/go-faster/sdk/app"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/simon/internal/generator"
+)
+
+func getEnvDefault(k, def string) string {
+	if v := os.Getenv(k); v != "" {
+		return v
+	}
+	return def
+}
+
+func cmdGen() *cobra.Command {
+	var arg struct {
+		Endpoint string
+		Protocol string
+		Config   generator.Config
+	}
+	cmd := &cobra.Command{
+		Use:   "gen",
+		Short: "Generate synthetic OTLP telemetry with imperfect delivery",
+		Run: func(cmd *cobra.Command, args []string) {
+			sdka.Run(func(ctx context.Context, lg *zap.Logger, t *sdka.Telemetry) error {
+				exp, err := generator.NewExporter(arg.Endpoint, arg.Protocol)
+				if err != nil {
+					return errors.Wrap(err, "exporter")
+				}
+				defer func() {
+					_ = exp.Close()
+				}()
+
+				g, err := generator.New(exp, t.MeterProvider(), arg.Config)
+				if err != nil {
+					return errors.Wrap(err, "generator")
+				}
+
+				lg.Info("Generating telemetry",
+					zap.String("endpoint", arg.Endpoint),
+					zap.String("protocol", arg.Protocol),
+				)
+				return g.Run(ctx)
+			},
+				sdka.WithServiceName("simon.gen"),
+			)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&arg.Endpoint, "endpoint", getEnvDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4317"), "OTLP endpoint")
+	f.StringVar(&arg.Protocol, "protocol", getEnvDefault("OTEL_EXPORTER_OTLP_PROTOCOL", generator.ProtocolGRPC), "OTLP protocol (grpc, http/protobuf)")
+	f.StringSliceVar(&arg.Config.Services, "services", []string{"simon.gen"}, "Service names of generated telemetry")
+	f.Float64Var(&arg.Config.TraceRate, "trace-rate", 1, "Traces per second")
+	f.IntVar(&arg.Config.SpansPerTrace, "spans", 5, "Spans per trace")
+	f.DurationVar(&arg.Config.BatchInterval, "batch-interval", time.Second, "Interval between export batches")
+
+	d := &arg.Config.Disorder
+	f.Float64Var(&d.LateRatio, "late-ratio", 0, "Probability of trace leaf span being sent after its parents")
+	f.DurationVar(&d.LateDelay, "late-delay", time.Second*30, "Delay of late spans")
+	f.Float64Var(&d.SplitRatio, "split-ratio", 0, "Probability of trace spans being split across batches")
+	f.IntVar(&d.SplitBatches, "split-batches", 3, "Number of batches to split trace across")
+	f.DurationVar(&d.SplitDelay, "split-delay", time.Second*10, "Delay between split trace batches")
+	f.Float64Var(&d.DuplicateRatio, "duplicate-ratio", 0, "Probability of exported batch being sent again")
+	f.DurationVar(&d.DuplicateDelay, "duplicate-delay", 0, "Delay of duplicate batches")
+	f.Float64Var(&d.PastRatio, "past-ratio", 0, "Probability of metric point having timestamp in the past")
+	f.DurationVar(&d.PastSkew, "past-skew", time.Minute*5, "How far in the past metric points are")
+
+	return cmd
+}
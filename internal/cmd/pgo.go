@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/go-faster/errors"
+	sdka "github.com/go-faster/sdk/app"
+	"github.com/google/pprof/profile"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/simon/internal/pgo"
+)
+
+func cmdPGO() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pgo",
+		Short: "Profile-guided optimization tools",
+	}
+	cmd.AddCommand(cmdPGOCollect())
+	return cmd
+}
+
+func cmdPGOCollect() *cobra.Command {
+	var arg struct {
+		Servers  []string
+		Clients  []string
+		Duration time.Duration
+		Output   string
+	}
+	cmd := &cobra.Command{
+		Use:   "collect",
+		Short: "Collect CPU profiles from running instances and write merged .pgo files",
+		Run: func(cmd *cobra.Command, args []string) {
+			sdka.Run(func(ctx context.Context, lg *zap.Logger, t *sdka.Telemetry) error {
+				if len(arg.Servers) == 0 && len(arg.Clients) == 0 {
+					return errors.New("no instances, use --server or --client")
+				}
+				client := &http.Client{Timeout: arg.Duration + 30*time.Second}
+				roles := []struct {
+					Name    string
+					Targets []string
+				}{
+					{Name: "server", Targets: arg.Servers},
+					{Name: "client", Targets: arg.Clients},
+				}
+
+				lg.Info("Collecting CPU profiles",
+					zap.Strings("servers", arg.Servers),
+					zap.Strings("clients", arg.Clients),
+					zap.Duration("duration", arg.Duration),
+				)
+				var (
+					all    = append(append([]string(nil), arg.Servers...), arg.Clients...)
+					byRole = map[string][]*profile.Profile{}
+					merged []*profile.Profile
+					offset int
+				)
+				profiles, err := pgo.Collect(ctx, client, all, arg.Duration)
+				if err != nil {
+					return errors.Wrap(err, "collect")
+				}
+				for _, role := range roles {
+					byRole[role.Name] = profiles[offset : offset+len(role.Targets)]
+					offset += len(role.Targets)
+				}
+
+				write := func(name string, list []*profile.Profile) error {
+					p, err := pgo.Merge(list...)
+					if err != nil {
+						return errors.Wrap(err, name)
+					}
+					out := filepath.Join(arg.Output, name)
+					if err := pgo.Write(out, p); err != nil {
+						return errors.Wrap(err, name)
+					}
+					lg.Info("Wrote profile",
+						zap.String("file", out),
+						zap.Int("profiles", len(list)),
+						zap.Int("samples", len(p.Sample)),
+					)
+					return nil
+				}
+				for _, role := range roles {
+					list := byRole[role.Name]
+					if len(list) == 0 {
+						continue
+					}
+					merged = append(merged, list...)
+					if err := write(role.Name+".pgo", list); err != nil {
+						return err
+					}
+				}
+				return write("default.pgo", merged)
+			},
+				sdka.WithServiceName("simon.pgo"),
+			)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&arg.Servers, "server", nil, "pprof address of server instance, repeatable")
+	cmd.Flags().StringSliceVar(&arg.Clients, "client", nil, "pprof address of client instance, repeatable")
+	cmd.Flags().DurationVar(&arg.Duration, "duration", 30*time.Second, "Duration of CPU profile")
+	cmd.Flags().StringVar(&arg.Output, "output", "cmd/simon", "Directory of .pgo files")
+
+	return cmd
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-faster/errors"
+	sdka "github.com/go-faster/sdk/app"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/simon/internal/generator"
+	"github.com/go-faster/simon/internal/probe"
+)
+
+func cmdProbe() *cobra.Command {
+	var arg struct {
+		Endpoint      string
+		Protocol      string
+		TempoURL      string
+		LokiURL       string
+		PrometheusURL string
+		Config        probe.Config
+	}
+	cmd := &cobra.Command{
+		Use:   "probe",
+		Short: "Measure ingestion-to-query latency with marker telemetry",
+		Run: func(cmd *cobra.Command, args []string) {
+			sdka.Run(func(ctx context.Context, lg *zap.Logger, t *sdka.Telemetry) error {
+				exp, err := generator.NewExporter(arg.Endpoint, arg.Protocol)
+				if err != nil {
+					return errors.Wrap(err, "exporter")
+				}
+				defer func() {
+					_ = exp.Close()
+				}()
+
+				client := &http.Client{Timeout: time.Second * 10}
+				cfg := arg.Config
+				cfg.Checkers = map[string]probe.Checker{}
+				if arg.TempoURL != "" {
+					cfg.Checkers[generator.SignalTraces] = probe.TempoChecker{
+						Client:  client,
+						BaseURL: arg.TempoURL,
+					}
+				}
+				if arg.LokiURL != "" {
+					cfg.Checkers[generator.SignalLogs] = probe.LokiChecker{
+						Client:   client,
+						BaseURL:  arg.LokiURL,
+						Selector: fmt.Sprintf("{service_name=%q}", cfg.Service),
+					}
+				}
+				if arg.PrometheusURL != "" {
+					cfg.Checkers[generator.SignalMetrics] = probe.PrometheusChecker{
+						Client:  client,
+						BaseURL: arg.PrometheusURL,
+					}
+				}
+				if len(cfg.Checkers) == 0 {
+					return errors.New("at least one of --tempo, --loki or --prometheus is required")
+				}
+
+				p, err := probe.New(exp, t.MeterProvider(), cfg)
+				if err != nil {
+					return errors.Wrap(err, "probe")
+				}
+
+				lg.Info("Probing", zap.Int("signals", len(cfg.Checkers)))
+				return p.Run(ctx)
+			},
+				sdka.WithServiceName("simon.probe"),
+			)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&arg.Endpoint, "endpoint", getEnvDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4317"), "OTLP endpoint to write markers to")
+	f.StringVar(&arg.Protocol, "protocol", getEnvDefault("OTEL_EXPORTER_OTLP_PROTOCOL", generator.ProtocolGRPC), "OTLP protocol (grpc, http/protobuf)")
+	f.StringVar(&arg.TempoURL, "tempo", "", "Tempo-compatible query API URL")
+	f.StringVar(&arg.LokiURL, "loki", "", "Loki-compatible query API URL")
+	f.StringVar(&arg.PrometheusURL, "prometheus", "", "Prometheus-compatible query API URL, e.g. http://oteldb:9090")
+	f.StringVar(&arg.Config.Service, "service", "simon.probe", "Service name of marker telemetry")
+	f.DurationVar(&arg.Config.Interval, "interval", time.Second*30, "Interval between probe rounds")
+	f.DurationVar(&arg.Config.PollInterval, "poll-interval", time.Second, "Interval between query attempts")
+	f.DurationVar(&arg.Config.Timeout, "timeout", time.Minute*2, "Time to wait for marker to become visible")
+
+	return cmd
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/KimMachineGun/automemlimit/memlimit"
+	"github.com/go-faster/errors"
+	sdka "github.com/go-faster/sdk/app"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/go-faster/simon/internal/workload"
+)
+
+func workloadConfig() (cfg workload.Config, err error) {
+	if cfg.CPU, err = getEnvFloat("WORKLOAD_CPU", 0); err != nil {
+		return cfg, err
+	}
+	if cfg.Alloc, err = getEnvInt("WORKLOAD_ALLOC", 0); err != nil {
+		return cfg, err
+	}
+	if cfg.Lock, err = getEnvInt("WORKLOAD_LOCK", 0); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// runWorkloads starts profile-shaping workloads from environment in group,
+// stopping them on shutdown.
+func runWorkloads(ctx context.Context, g *errgroup.Group, lg *zap.Logger, t *sdka.Telemetry) error {
+	cfg, err := workloadConfig()
+	if err != nil {
+		return errors.Wrap(err, "workload config")
+	}
+	if !cfg.Enabled() {
+		return nil
+	}
+	lg.Info("Starting workloads",
+		zap.Float64("cpu", cfg.CPU),
+		zap.Int("alloc", cfg.Alloc),
+		zap.Int("lock", cfg.Lock),
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(t.ShutdownContext(), cancel)
+	g.Go(func() error {
+		defer cancel()
+		defer stop()
+		return workload.Run(ctx, t.TracerProvider(), cfg)
+	})
+	return nil
+}
+
+// setupPressure creates runtime-pressure behaviors with defaults from
+// BEHAVIORS, overridable per request if BEHAVIORS_HEADER is set.
+func setupPressure(t *sdka.Telemetry) (*workload.Pressure, workload.BehaviorOptions, error) {
+	var opts workload.BehaviorOptions
+	defaults, err := workload.ParseBehaviors(os.Getenv("BEHAVIORS"))
+	if err != nil {
+		return nil, opts, errors.Wrap(err, "parse BEHAVIORS")
+	}
+	opts.Defaults = defaults
+	opts.Header = getEnvBool("BEHAVIORS_HEADER")
+	p, err := workload.NewPressure(t.TracerProvider(), t.MeterProvider())
+	if err != nil {
+		return nil, opts, errors.Wrap(err, "pressure")
+	}
+	return p, opts, nil
+}
+
+func memoryConfig() (cfg workload.MemoryConfig, err error) {
+	for _, v := range []struct {
+		k string
+		n *int
+	}{
+		{"MEMORY_HOLD", &cfg.Hold},
+		{"MEMORY_TARGET", &cfg.Target},
+		{"MEMORY_GROWTH", &cfg.Growth},
+		{"MEMORY_LEAK", &cfg.Leak},
+		{"MEMORY_CRASH_AT", &cfg.CrashAt},
+	} {
+		if *v.n, err = getEnvInt(v.k, 0); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// setupMemory sets GOMEMLIMIT from MEMORY_LIMIT and starts memory workload
+// from environment in group, stopping it on shutdown.
+func setupMemory(ctx context.Context, g *errgroup.Group, lg *zap.Logger, t *sdka.Telemetry) (*workload.Memory, error) {
+	limit, err := getEnvInt("MEMORY_LIMIT", 0)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 {
+		// Same as cgroup limit detected by SDK, ratio is set by AUTOMEMLIMIT.
+		v, err := memlimit.SetGoMemLimitWithOpts(
+			memlimit.WithProvider(memlimit.Limit(uint64(limit) << 20)),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "set memory limit")
+		}
+		lg.Info("Memory limit set", zap.Int64("GOMEMLIMIT", v))
+	}
+	cfg, err := memoryConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "memory config")
+	}
+	m, err := workload.NewMemory(cfg, t.MeterProvider())
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return m, nil
+	}
+	lg.Info("Starting memory workload",
+		zap.Int("hold_mib", cfg.Hold),
+		zap.Int("target_mib", cfg.Target),
+		zap.Int("growth_mib", cfg.Growth),
+		zap.Int("leak_kib", cfg.Leak),
+		zap.Int("crash_at_mib", cfg.CrashAt),
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(t.ShutdownContext(), cancel)
+	g.Go(func() error {
+		defer cancel()
+		defer stop()
+		if err := m.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			return errors.Wrap(err, "run memory workload")
+		}
+		return nil
+	})
+	return m, nil
+}
@@ -30,6 +30,14 @@ func Root() *cobra.Command {
 	cmd.AddCommand(
 		cmdServer(),
 		cmdClient(),
+		cmdGen(),
+		cmdSink(),
+		cmdProbe(),
+		cmdVerify(),
+		cmdCache(),
+		cmdBroker(),
+		cmdPGO(),
+		cmdCerts(),
 	)
 	return cmd
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"os"
@@ -13,13 +14,29 @@ import (
 	sdka "github.com/go-faster/sdk/app"
 	"github.com/rs/cors"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
 	"github.com/go-faster/simon/internal/app"
+	"github.com/go-faster/simon/internal/auth"
+	"github.com/go-faster/simon/internal/baggage"
+	"github.com/go-faster/simon/internal/broker"
+	"github.com/go-faster/simon/internal/crash"
+	"github.com/go-faster/simon/internal/middleware"
 	"github.com/go-faster/simon/internal/oas"
+	"github.com/go-faster/simon/internal/resp"
 	"github.com/go-faster/simon/internal/server"
+	"github.com/go-faster/simon/internal/shed"
+	"github.com/go-faster/simon/internal/simonpb"
+	"github.com/go-faster/simon/internal/slow"
+	"github.com/go-faster/simon/internal/tlsconfig"
 )
 
 type zapCorsLogger struct {
@@ -35,6 +52,284 @@ func getEnvBool(k string) bool {
 	return v
 }
 
+func getEnvDuration(k string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(k)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parse %s", k)
+	}
+	return d, nil
+}
+
+func getEnvInt(k string, def int) (int, error) {
+	v := os.Getenv(k)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parse %s", k)
+	}
+	return n, nil
+}
+
+func getEnvFloat(k string, def float64) (float64, error) {
+	v := os.Getenv(k)
+	if v == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parse %s", k)
+	}
+	return f, nil
+}
+
+func healthOptions() (opts server.HealthOptions, err error) {
+	for _, v := range []struct {
+		k string
+		d *time.Duration
+	}{
+		{"STARTUP_DELAY", &opts.StartupDelay},
+		{"LIVENESS_FLAP_PERIOD", &opts.LivenessFlap.Period},
+		{"LIVENESS_FLAP_DOWN", &opts.LivenessFlap.Down},
+		{"READINESS_FLAP_PERIOD", &opts.ReadinessFlap.Period},
+		{"READINESS_FLAP_DOWN", &opts.ReadinessFlap.Down},
+		{"HEALTH_CHECK_TIMEOUT", &opts.CheckTimeout},
+	} {
+		if *v.d, err = getEnvDuration(v.k, 0); err != nil {
+			return opts, err
+		}
+	}
+	opts.CheckExternal = getEnvBool("HEALTH_CHECK_EXTERNAL")
+	return opts, nil
+}
+
+type httpMiddlewareOptions struct {
+	Recover           bool
+	Timeout           time.Duration
+	BodyLimit         int64
+	ClientConcurrency int
+}
+
+func middlewareOptions() (opts httpMiddlewareOptions, err error) {
+	opts.Recover = true
+	if v := os.Getenv("HTTP_RECOVER"); v != "" {
+		if opts.Recover, err = strconv.ParseBool(v); err != nil {
+			return opts, errors.Wrap(err, "parse HTTP_RECOVER")
+		}
+	}
+	if opts.Timeout, err = getEnvDuration("HTTP_TIMEOUT", 0); err != nil {
+		return opts, err
+	}
+	bodyLimit, err := getEnvInt("HTTP_BODY_LIMIT", 0)
+	if err != nil {
+		return opts, err
+	}
+	opts.BodyLimit = int64(bodyLimit)
+	if opts.ClientConcurrency, err = getEnvInt("HTTP_CLIENT_CONCURRENCY", 0); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+type httpTimeouts struct {
+	ReadHeader time.Duration
+	Read       time.Duration
+	Write      time.Duration
+	Idle       time.Duration
+}
+
+func serverTimeouts() (opts httpTimeouts, err error) {
+	for _, v := range []struct {
+		k   string
+		d   *time.Duration
+		def time.Duration
+	}{
+		{"HTTP_READ_HEADER_TIMEOUT", &opts.ReadHeader, time.Second},
+		{"HTTP_READ_TIMEOUT", &opts.Read, time.Second},
+		{"HTTP_WRITE_TIMEOUT", &opts.Write, time.Second},
+		{"HTTP_IDLE_TIMEOUT", &opts.Idle, 0},
+	} {
+		if *v.d, err = getEnvDuration(v.k, v.def); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+func slowOptions() (opts slow.ServerOptions, err error) {
+	if opts.Ratio, err = getEnvFloat("SLOW_RATIO", 0); err != nil {
+		return opts, err
+	}
+	if opts.TrickleDelay, err = getEnvDuration("SLOW_TRICKLE_DELAY", 0); err != nil {
+		return opts, err
+	}
+	if opts.TrickleChunk, err = getEnvInt("SLOW_TRICKLE_CHUNK", 1); err != nil {
+		return opts, err
+	}
+	if opts.StallAfter, err = getEnvInt("SLOW_STALL_AFTER", 0); err != nil {
+		return opts, err
+	}
+	if opts.StallFor, err = getEnvDuration("SLOW_STALL", 0); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+func shedOptions() (opts shed.Options, err error) {
+	if opts.RPS, err = getEnvFloat("RATE_LIMIT_RPS", 0); err != nil {
+		return opts, err
+	}
+	if opts.Burst, err = getEnvInt("RATE_LIMIT_BURST", 0); err != nil {
+		return opts, err
+	}
+	opts.KeyHeader = os.Getenv("RATE_LIMIT_KEY_HEADER")
+	if opts.MaxConcurrency, err = getEnvInt("CONCURRENCY_LIMIT", 0); err != nil {
+		return opts, err
+	}
+	if opts.QueueSize, err = getEnvInt("CONCURRENCY_QUEUE_SIZE", 0); err != nil {
+		return opts, err
+	}
+	if opts.QueueTimeout, err = getEnvDuration("CONCURRENCY_QUEUE_TIMEOUT", 0); err != nil {
+		return opts, err
+	}
+	if opts.LatencyTarget, err = getEnvDuration("SHED_LATENCY_TARGET", 0); err != nil {
+		return opts, err
+	}
+	// Shedding probes would restart healthy pods.
+	opts.Skip = probeRequest
+	return opts, nil
+}
+
+// probeRequest reports whether request is health probe, which failure
+// and pressure middlewares skip, so probes reflect health of the process only.
+func probeRequest(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz", "/startupz":
+		return true
+	default:
+		return false
+	}
+}
+
+func splitEnv(k string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(k), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func authOptions() (opts auth.Options, err error) {
+	opts.APIKeys = splitEnv("AUTH_API_KEYS")
+	opts.Tokens = splitEnv("AUTH_BEARER_TOKENS")
+	opts.JWTKey = []byte(os.Getenv("AUTH_JWT_KEY"))
+	opts.Scope = getEnvDefault("AUTH_JWT_SCOPE", "upload")
+	if opts.Latency, err = getEnvDuration("AUTH_LATENCY", 0); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// serverTLS returns TLS config from environment, nil if TLS is disabled.
+func serverTLS() (*tls.Config, error) {
+	opts := tlsconfig.ServerOptions{
+		CertFile:     os.Getenv("TLS_CERT_FILE"),
+		KeyFile:      os.Getenv("TLS_KEY_FILE"),
+		ClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+		Hosts:        splitEnv("TLS_HOSTS"),
+	}
+	if !getEnvBool("TLS_ENABLE") && opts.CertFile == "" {
+		return nil, nil
+	}
+	if len(opts.Hosts) == 0 {
+		opts.Hosts = []string{"localhost", "127.0.0.1"}
+		if hostname, err := os.Hostname(); err == nil {
+			opts.Hosts = append(opts.Hosts, hostname)
+		}
+	}
+	return tlsconfig.Server(opts)
+}
+
+func crashOptions() (opts crash.Options, err error) {
+	for _, v := range []struct {
+		k string
+		n *int
+	}{
+		{"CRASH_PANIC_EVERY", &opts.PanicEvery},
+		{"CRASH_EXIT_AFTER_REQUESTS", &opts.ExitAfterRequests},
+		{"CRASH_DEADLOCK_AFTER_REQUESTS", &opts.DeadlockAfterRequests},
+	} {
+		if *v.n, err = getEnvInt(v.k, 0); err != nil {
+			return opts, err
+		}
+	}
+	for _, v := range []struct {
+		k string
+		d *time.Duration
+	}{
+		{"CRASH_EXIT_AFTER", &opts.ExitAfter},
+		{"CRASH_DEADLOCK_TIMEOUT", &opts.DeadlockTimeout},
+		{"CRASH_SHUTDOWN_HANG", &opts.ShutdownHang},
+	} {
+		if *v.d, err = getEnvDuration(v.k, 0); err != nil {
+			return opts, err
+		}
+	}
+	if os.Getenv("CRASH_EXIT_CODE") != "" {
+		code, err := getEnvInt("CRASH_EXIT_CODE", 0)
+		if err != nil {
+			return opts, err
+		}
+		opts.ExitCode = &code
+	}
+	// Requests are counted without probes, so failures are triggered
+	// by traffic and probe interval does not change them.
+	opts.Skip = probeRequest
+	return opts, nil
+}
+
+// setupCache creates RESP client from environment, starting embedded
+// RESP server in group if CACHE_ADDR is not set.
+func setupCache(ctx context.Context, g *errgroup.Group, lg *zap.Logger, t *sdka.Telemetry) (*resp.Client, error) {
+	addr := os.Getenv("CACHE_ADDR")
+	if addr == "" {
+		var opts resp.Options
+		var err error
+		if opts.Latency, err = getEnvDuration("CACHE_LATENCY", 0); err != nil {
+			return nil, err
+		}
+		if opts.Jitter, err = getEnvDuration("CACHE_JITTER", 0); err != nil {
+			return nil, err
+		}
+		if opts.MissRatio, err = getEnvFloat("CACHE_MISS_RATIO", 0); err != nil {
+			return nil, err
+		}
+		ln, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			return nil, errors.Wrap(err, "listen cache")
+		}
+		addr = ln.Addr().String()
+		lg.Info("Starting embedded RESP server", zap.String("addr", addr))
+
+		// Stop embedded server on shutdown.
+		ctx, cancel := context.WithCancel(ctx)
+		stop := context.AfterFunc(t.ShutdownContext(), cancel)
+		g.Go(func() error {
+			defer cancel()
+			defer stop()
+			return resp.NewServer(opts).Serve(ctx, ln)
+		})
+	}
+	return resp.NewClient(addr, 16, t.TracerProvider(), t.MeterProvider())
+}
+
 func cmdServer() *cobra.Command {
 	return &cobra.Command{
 		Use:   "server",
@@ -46,12 +341,70 @@ func cmdServer() *cobra.Command {
 					addr = "localhost:8080"
 				}
 				lg.Info("Listening on", zap.String("addr", addr))
+
+				g, ctx := errgroup.WithContext(ctx)
+				if err := runWorkloads(ctx, g, lg, t); err != nil {
+					return err
+				}
+				memory, err := setupMemory(ctx, g, lg, t)
+				if err != nil {
+					return errors.Wrap(err, "memory")
+				}
+				var opts server.Options
+				if getEnvBool("CACHE_ENABLE") {
+					cache, err := setupCache(ctx, g, lg, t)
+					if err != nil {
+						return errors.Wrap(err, "cache")
+					}
+					defer func() {
+						_ = cache.Close()
+					}()
+					ttl, err := getEnvDuration("CACHE_TTL", time.Minute)
+					if err != nil {
+						return err
+					}
+					opts.Cache = cache
+					opts.CacheTTL = ttl
+				}
+				healthOpts, err := healthOptions()
+				if err != nil {
+					return errors.Wrap(err, "health")
+				}
+				opts.Health = healthOpts
+				authOpts, err := authOptions()
+				if err != nil {
+					return errors.Wrap(err, "auth")
+				}
+				authenticator, err := auth.New(authOpts, t.TracerProvider(), t.MeterProvider())
+				if err != nil {
+					return errors.Wrap(err, "auth")
+				}
+				opts.Auth = authenticator
+				downloadMaxSize, err := getEnvInt("DOWNLOAD_MAX_SIZE", server.DefaultDownloadMaxSize)
+				if err != nil {
+					return err
+				}
+				opts.DownloadMaxSize = int64(downloadMaxSize)
+				if opts.Downstream, err = server.ParseDownstream(getEnvDefault("DOWNSTREAM", server.DefaultDownstream)); err != nil {
+					return errors.Wrap(err, "parse DOWNSTREAM")
+				}
+				if v := os.Getenv("BROKER_ADDR"); v != "" {
+					b, err := broker.NewClient(v, t.TracerProvider(), t.MeterProvider())
+					if err != nil {
+						return errors.Wrap(err, "broker")
+					}
+					opts.Broker = b
+					opts.BrokerTopic = getEnvDefault("BROKER_TOPIC", "simon.events")
+					opts.BrokerGroup = getEnvDefault("BROKER_GROUP", "simon.server")
+				}
 				srv := server.NewServer(
 					t.TracerProvider(),
+					opts,
 				)
-				h, err := oas.NewServer(srv,
+				h, err := oas.NewServer(srv, authenticator,
 					oas.WithMeterProvider(t.MeterProvider()),
 					oas.WithTracerProvider(t.TracerProvider()),
+					oas.WithMiddleware(server.RawRequest),
 				)
 				if err != nil {
 					return err
@@ -71,36 +424,217 @@ func cmdServer() *cobra.Command {
 
 				c.Log = zapCorsLogger{lg: lg.Sugar()}
 
-				spanNameFormatter := app.NewSpanNameFormatter(h)
-				instrumentedHandler := otelhttp.NewHandler(c.Handler(h), "",
-					otelhttp.WithSpanNameFormatter(spanNameFormatter),
-					otelhttp.WithMeterProvider(t.MeterProvider()),
-					otelhttp.WithTracerProvider(t.TracerProvider()),
-				)
+				crashOpts, err := crashOptions()
+				if err != nil {
+					return errors.Wrap(err, "crash")
+				}
+				crasher := crash.New(crashOpts, t.TracerProvider(), t.MeterProvider(), t.LoggerProvider())
+				g.Go(func() error {
+					// Stop crash timers on shutdown.
+					ctx, cancel := context.WithCancel(ctx)
+					defer cancel()
+					stop := context.AfterFunc(t.ShutdownContext(), cancel)
+					defer stop()
+					if err := crasher.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+						return errors.Wrap(err, "run crasher")
+					}
+					return nil
+				})
+				g.Go(func() error {
+					select {
+					case <-ctx.Done():
+						// Startup or server failure, not shutdown.
+						return nil
+					case <-t.ShutdownContext().Done():
+						crasher.HangShutdown(t.BaseContext())
+						return nil
+					}
+				})
+
+				bagOpts := baggage.Options{Keys: splitEnv("BAGGAGE_ATTRIBUTES")}
+				if bagOpts.Latency, err = getEnvDuration("BAGGAGE_FAULT_LATENCY", 0); err != nil {
+					return err
+				}
+				bag, err := baggage.New(bagOpts, t.MeterProvider())
+				if err != nil {
+					return errors.Wrap(err, "baggage")
+				}
+				slowOpts, err := slowOptions()
+				if err != nil {
+					return errors.Wrap(err, "slow")
+				}
+				slowServer, err := slow.NewServer(slowOpts, t.MeterProvider())
+				if err != nil {
+					return errors.Wrap(err, "slow")
+				}
+				pressure, behaviors, err := setupPressure(t)
+				if err != nil {
+					return err
+				}
+				if len(behaviors.Defaults) > 0 || behaviors.Header {
+					lg.Info("Applying behaviors",
+						zap.Stringer("behaviors", behaviors.Defaults),
+						zap.Bool("header", behaviors.Header),
+					)
+				}
+
+				mwOpts, err := middlewareOptions()
+				if err != nil {
+					return errors.Wrap(err, "middleware")
+				}
+				shedOpts, err := shedOptions()
+				if err != nil {
+					return errors.Wrap(err, "shed")
+				}
+				shedder, err := shed.New(shedOpts, t.MeterProvider())
+				if err != nil {
+					return errors.Wrap(err, "shed")
+				}
+				g.Go(func() error {
+					ctx, cancel := context.WithCancel(ctx)
+					defer cancel()
+					stop := context.AfterFunc(t.ShutdownContext(), cancel)
+					defer stop()
+					if err := shedder.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+						return errors.Wrap(err, "run shedder")
+					}
+					return nil
+				})
+				var recoverPanics middleware.Middleware
+				if mwOpts.Recover {
+					recoverPanics = middleware.Recover()
+				}
+				chain := middleware.Chain{
+					otelhttp.NewMiddleware("",
+						otelhttp.WithSpanNameFormatter(app.NewSpanNameFormatter(h)),
+						otelhttp.WithMeterProvider(t.MeterProvider()),
+						otelhttp.WithTracerProvider(t.TracerProvider()),
+					),
+					middleware.RequestID(),
+					bag.Middleware(),
+					middleware.Log(),
+					// Closest to connection, so buffering middlewares do not hide it.
+					middleware.Unless(probeRequest, slowServer.Middleware()),
+					c.Handler,
+					recoverPanics,
+					shedder.Middleware(),
+					middleware.Timeout(mwOpts.Timeout),
+					middleware.BodyLimit(mwOpts.BodyLimit),
+					middleware.ConcurrencyLimit(mwOpts.ClientConcurrency, nil),
+					middleware.Unless(probeRequest, pressure.Middleware(behaviors)),
+					middleware.Unless(probeRequest, memory.Middleware()),
+					crasher.Middleware(),
+					middleware.Flusher(),
+				}
+				http2MaxStreams, err := getEnvInt("HTTP2_MAX_CONCURRENT_STREAMS", 0)
+				if err != nil {
+					return err
+				}
+				timeouts, err := serverTimeouts()
+				if err != nil {
+					return errors.Wrap(err, "timeouts")
+				}
 				s := &http.Server{
 					Addr:              addr,
-					ReadHeaderTimeout: time.Second,
-					WriteTimeout:      time.Second,
-					ReadTimeout:       time.Second,
-					Handler:           instrumentedHandler,
+					ReadHeaderTimeout: timeouts.ReadHeader,
+					WriteTimeout:      timeouts.Write,
+					ReadTimeout:       timeouts.Read,
+					IdleTimeout:       timeouts.Idle,
+					Handler:           chain.Then(h),
 					BaseContext: func(listener net.Listener) context.Context {
 						return t.BaseContext()
 					},
+					HTTP2: &http.HTTP2Config{
+						MaxConcurrentStreams: http2MaxStreams,
+					},
+				}
+				// HTTP/2 over TLS is negotiated by ALPN, cleartext uses prior knowledge (h2c).
+				s.Protocols = new(http.Protocols)
+				s.Protocols.SetHTTP1(true)
+				s.Protocols.SetHTTP2(true)
+				s.Protocols.SetUnencryptedHTTP2(true)
+
+				tlsConfig, err := serverTLS()
+				if err != nil {
+					return errors.Wrap(err, "tls")
+				}
+				tlsMetrics, err := tlsconfig.NewMetrics(t.TracerProvider(), t.MeterProvider())
+				if err != nil {
+					return errors.Wrap(err, "tls metrics")
 				}
 
-				lg.Info("Starting HTTP server", zap.String("addr", addr))
+				lg.Info("Starting HTTP server",
+					zap.String("addr", addr),
+					zap.Bool("tls", tlsConfig != nil),
+				)
+
+				g.Go(func() error {
+					// Stop background workers on shutdown.
+					ctx, cancel := context.WithCancel(ctx)
+					defer cancel()
+					stop := context.AfterFunc(t.ShutdownContext(), cancel)
+					defer stop()
+					if err := srv.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+						return errors.Wrap(err, "run server workers")
+					}
+					return nil
+				})
+				grpcAddr := getEnvDefault("GRPC_ADDR", "localhost:8081")
+				unaryLogger, streamLogger := server.LoggerInterceptors(lg)
+				grpcOpts := []grpc.ServerOption{
+					grpc.StatsHandler(otelgrpc.NewServerHandler(
+						otelgrpc.WithMeterProvider(t.MeterProvider()),
+						otelgrpc.WithTracerProvider(t.TracerProvider()),
+					)),
+					grpc.ChainUnaryInterceptor(unaryLogger, bag.UnaryInterceptor(), pressure.UnaryInterceptor(behaviors)),
+					grpc.ChainStreamInterceptor(streamLogger,
+						authenticator.StreamInterceptor(simonpb.SimonService_UploadFile_FullMethodName),
+						bag.StreamInterceptor(),
+						pressure.StreamInterceptor(behaviors),
+					),
+				}
+				if tlsConfig != nil {
+					grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+				}
+				grpcServer := grpc.NewServer(grpcOpts...)
+				srv.RegisterGRPC(grpcServer)
+				healthServer := health.NewServer()
+				healthpb.RegisterHealthServer(grpcServer, healthServer)
+				reflection.Register(grpcServer)
 
-				g, ctx := errgroup.WithContext(ctx)
 				g.Go(func() error {
 					select {
 					case <-ctx.Done():
+						// Either server failed, tear down both.
+						grpcServer.Stop()
+						_ = s.Close()
 						return ctx.Err()
 					case <-t.ShutdownContext().Done():
+						healthServer.Shutdown()
+						grpcServer.GracefulStop()
 						return s.Shutdown(t.BaseContext())
 					}
 				})
 				g.Go(func() error {
-					if err := s.ListenAndServe(); err != nil {
+					ln, err := net.Listen("tcp", grpcAddr)
+					if err != nil {
+						return errors.Wrap(err, "listen grpc")
+					}
+					lg.Info("Starting gRPC server", zap.String("addr", grpcAddr))
+					if err := grpcServer.Serve(ln); err != nil {
+						return errors.Wrap(err, "grpc server")
+					}
+					return nil
+				})
+				g.Go(func() error {
+					ln, err := net.Listen("tcp", addr)
+					if err != nil {
+						return errors.Wrap(err, "listen http")
+					}
+					if tlsConfig != nil {
+						ln = tlsMetrics.Listener(ln, tlsConfig)
+					}
+					if err := s.Serve(ln); err != nil {
 						if errors.Is(err, http.ErrServerClosed) {
 							lg.Info("HTTP server closed gracefully")
 							return nil
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-faster/errors"
+	sdka "github.com/go-faster/sdk/app"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+
+	"github.com/go-faster/simon/internal/sink"
+)
+
+func cmdSink() *cobra.Command {
+	var arg struct {
+		GRPCAddr  string
+		HTTPAddr  string
+		Chaos     string
+		MaxTraces int
+	}
+	cmd := &cobra.Command{
+		Use:   "sink",
+		Short: "Run OTLP receiver that verifies delivered telemetry",
+		Run: func(cmd *cobra.Command, args []string) {
+			sdka.Run(func(ctx context.Context, lg *zap.Logger, t *sdka.Telemetry) error {
+				var chaos *sink.Chaos
+				if arg.Chaos != "" {
+					cfg, err := sink.ReadChaosConfig(arg.Chaos)
+					if err != nil {
+						return errors.Wrap(err, "chaos config")
+					}
+					chaos = sink.NewChaos(cfg)
+					lg.Info("Chaos enabled", zap.Int("phases", len(cfg.Phases)))
+				}
+				s, err := sink.New(t.MeterProvider(), sink.Options{
+					Chaos:     chaos,
+					MaxTraces: arg.MaxTraces,
+				})
+				if err != nil {
+					return errors.Wrap(err, "sink")
+				}
+
+				grpcServer := grpc.NewServer()
+				s.RegisterGRPC(grpcServer)
+				httpServer := &http.Server{
+					Addr:              arg.HTTPAddr,
+					ReadHeaderTimeout: time.Second,
+					Handler:           s.Handler(),
+					BaseContext: func(listener net.Listener) context.Context {
+						return t.BaseContext()
+					},
+				}
+
+				g, ctx := errgroup.WithContext(ctx)
+				g.Go(func() error {
+					select {
+					case <-ctx.Done():
+						grpcServer.Stop()
+						return ctx.Err()
+					case <-t.ShutdownContext().Done():
+						grpcServer.GracefulStop()
+						return httpServer.Shutdown(t.BaseContext())
+					}
+				})
+				g.Go(func() error {
+					ln, err := net.Listen("tcp", arg.GRPCAddr)
+					if err != nil {
+						return errors.Wrap(err, "listen grpc")
+					}
+					lg.Info("Starting OTLP gRPC receiver", zap.String("addr", arg.GRPCAddr))
+					if err := grpcServer.Serve(chaos.Listener(ln)); err != nil {
+						return errors.Wrap(err, "grpc server")
+					}
+					return nil
+				})
+				g.Go(func() error {
+					lg.Info("Starting OTLP HTTP receiver", zap.String("addr", arg.HTTPAddr))
+					if err := httpServer.ListenAndServe(); err != nil {
+						if errors.Is(err, http.ErrServerClosed) {
+							lg.Info("HTTP server closed gracefully")
+							return nil
+						}
+						return errors.Wrap(err, "http server")
+					}
+					return nil
+				})
+				return g.Wait()
+			},
+				sdka.WithServiceName("simon.sink"),
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&arg.GRPCAddr, "grpc-addr", "localhost:4317", "OTLP gRPC listen address")
+	cmd.Flags().StringVar(&arg.HTTPAddr, "http-addr", "localhost:4318", "OTLP HTTP and stats API listen address")
+	cmd.Flags().IntVar(&arg.MaxTraces, "max-traces", 10_000, "Number of recent traces kept for verification")
+	cmd.Flags().StringVar(&arg.Chaos, "chaos", "", "Path to chaos config, enables misbehaving receivers")
+
+	return cmd
+}
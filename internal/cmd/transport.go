@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-faster/simon/internal/slow"
+)
+
+// Client HTTP protocols.
+const (
+	httpProtocol1      = "http1"
+	httpProtocol1Close = "http1-close"
+	httpProtocol2      = "http2"
+)
+
+type transportOptions struct {
+	// Protocol is one of http1, http1-close or http2.
+	Protocol string
+	// MaxStreams limits concurrent HTTP/2 requests, zero is server limit.
+	MaxStreams int
+
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	DialTimeout         time.Duration
+	// Trace enables connection lifecycle tracing.
+	Trace bool
+	// Slow limits bandwidth of bodies.
+	Slow slow.TransportOptions
+}
+
+// newTransport creates HTTP transport for protocol, should be wrapped
+// by otelhttp.
+//
+// HTTP/2 uses prior knowledge (h2c) for plain http:// addresses and ALPN
+// for https://, multiplexing requests over single connection.
+func newTransport(opts transportOptions, tlsConfig *tls.Config) (http.RoundTripper, error) {
+	next, err := newProtocolTransport(opts, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return protocolReporter{next: slow.Transport(next, opts.Slow)}, nil
+}
+
+func newProtocolTransport(opts transportOptions, tlsConfig *tls.Config) (http.RoundTripper, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.DialContext = (&net.Dialer{
+		Timeout:   opts.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.MaxIdleConns = opts.MaxIdleConns
+	transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = opts.IdleConnTimeout
+	transport.Protocols = new(http.Protocols)
+	switch opts.Protocol {
+	case httpProtocol1:
+		transport.Protocols.SetHTTP1(true)
+	case httpProtocol1Close:
+		transport.Protocols.SetHTTP1(true)
+		transport.DisableKeepAlives = true
+	case httpProtocol2:
+		transport.Protocols.SetHTTP2(true)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+		// Block on server stream limit instead of opening new connections.
+		transport.MaxConnsPerHost = 1
+	default:
+		return nil, errors.Errorf("unknown http protocol %q", opts.Protocol)
+	}
+	if opts.Protocol != httpProtocol2 || opts.MaxStreams <= 0 {
+		return transport, nil
+	}
+	return &streamLimiter{
+		next: transport,
+		sem:  make(chan struct{}, opts.MaxStreams),
+	}, nil
+}
+
+// streamLimiter limits concurrent requests until response body is closed.
+type streamLimiter struct {
+	next http.RoundTripper
+	sem  chan struct{}
+}
+
+func (l *streamLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case l.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	resp, err := l.next.RoundTrip(req)
+	if err != nil {
+		<-l.sem
+		return nil, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: func() { <-l.sem }}
+	return resp, nil
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// protocolReporter sets negotiated protocol version on client span,
+// otelhttp reports version of request which is always HTTP/1.1.
+type protocolReporter struct {
+	next http.RoundTripper
+}
+
+func (p protocolReporter) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := p.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	version := strconv.Itoa(resp.ProtoMajor)
+	if resp.ProtoMajor < 2 {
+		version += "." + strconv.Itoa(resp.ProtoMinor)
+	}
+	trace.SpanFromContext(req.Context()).SetAttributes(semconv.NetworkProtocolVersion(version))
+	return resp, nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-faster/errors"
+	sdka "github.com/go-faster/sdk/app"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/simon/internal/verify"
+)
+
+func cmdVerify() *cobra.Command {
+	var arg struct {
+		Source    string
+		URL       string
+		Service   string
+		Lookback  time.Duration
+		MinAge    time.Duration
+		Limit     int
+		ClockSkew time.Duration
+		Interval  time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify completeness of generated traces",
+		Run: func(cmd *cobra.Command, args []string) {
+			sdka.Run(func(ctx context.Context, lg *zap.Logger, t *sdka.Telemetry) error {
+				client := &http.Client{Timeout: time.Second * 30}
+				var src verify.Source
+				switch arg.Source {
+				case "sink":
+					src = verify.SinkSource{Client: client, BaseURL: arg.URL}
+				case "tempo":
+					src = verify.TempoSource{Client: client, BaseURL: arg.URL}
+				case "jaeger":
+					src = verify.JaegerSource{Client: client, BaseURL: arg.URL, Service: arg.Service}
+				default:
+					return errors.Errorf("unknown source %q", arg.Source)
+				}
+
+				meter := t.MeterProvider().Meter("simon.verify")
+				traces, err := meter.Int64Counter("simon.verify.traces",
+					metric.WithDescription("Number of verified traces"),
+				)
+				if err != nil {
+					return errors.Wrap(err, "traces counter")
+				}
+				anomalies, err := meter.Int64Counter("simon.verify.anomalies",
+					metric.WithDescription("Number of detected trace anomalies"),
+				)
+				if err != nil {
+					return errors.Wrap(err, "anomalies counter")
+				}
+
+				v := verify.Verifier{ClockSkew: arg.ClockSkew}
+				round := func(until time.Time) error {
+					list, err := src.Traces(ctx, until.Add(-arg.Lookback), until, arg.Limit)
+					if err != nil {
+						return errors.Wrap(err, "fetch traces")
+					}
+					var summary verify.Summary
+					for _, tr := range list {
+						r := v.Verify(tr)
+						summary.Add(r)
+						if !r.Verifiable && !r.RootMissing {
+							continue
+						}
+						result := "complete"
+						if !r.Complete() {
+							result = "incomplete"
+							lg.Warn("Incomplete trace", zap.Any("report", r))
+						}
+						traces.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+						for kind, n := range map[string]int{
+							"missing_span":   r.MissingSpans,
+							"broken_link":    r.BrokenLinks,
+							"orphan_span":    r.OrphanSpans,
+							"clock_anomaly":  r.ClockAnomalies,
+							"duplicate_span": r.DuplicateSpans,
+						} {
+							if n > 0 {
+								anomalies.Add(ctx, int64(n), metric.WithAttributes(attribute.String("kind", kind)))
+							}
+						}
+					}
+					e := json.NewEncoder(os.Stdout)
+					e.SetIndent("", "  ")
+					return e.Encode(summary)
+				}
+
+				if arg.Interval <= 0 {
+					return round(time.Now().Add(-arg.MinAge))
+				}
+				// Continuous mode: verify non-overlapping windows, --lookback
+				// is rejected by flag group.
+				arg.Lookback = arg.Interval
+				ticker := time.NewTicker(arg.Interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case now := <-ticker.C:
+						if err := round(now.Add(-arg.MinAge)); err != nil {
+							lg.Error("Verification failed", zap.Error(err))
+						}
+					}
+				}
+			},
+				sdka.WithServiceName("simon.verify"),
+			)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&arg.Source, "source", "sink", "Trace source (sink, tempo, jaeger)")
+	f.StringVar(&arg.URL, "url", "http://localhost:4318", "Trace source API URL")
+	f.StringVar(&arg.Service, "service", "simon.gen", "Service name to query, for jaeger source")
+	f.DurationVar(&arg.Lookback, "lookback", time.Minute*5, "Verify traces from this long ago, one-shot only")
+	f.DurationVar(&arg.MinAge, "min-age", time.Minute, "Skip traces younger than this, so late spans can arrive")
+	f.IntVar(&arg.Limit, "limit", 1000, "Maximum number of traces to verify")
+	f.DurationVar(&arg.ClockSkew, "clock-skew", 0, "Tolerated skew between child and parent span bounds")
+	f.DurationVar(&arg.Interval, "interval", 0, "Verify continuously with this interval, one-shot if zero; each round verifies traces of last interval")
+	cmd.MarkFlagsMutuallyExclusive("lookback", "interval")
+
+	return cmd
+}
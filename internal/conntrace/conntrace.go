@@ -0,0 +1,178 @@
+// Package conntrace records client connection lifecycle with httptrace.
+package conntrace
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Phases of request, value of phase attribute.
+const (
+	// PhaseDNS is host lookup.
+	PhaseDNS = "dns"
+	// PhaseConnect is dial of single address.
+	PhaseConnect = "connect"
+	// PhaseTLS is TLS handshake.
+	PhaseTLS = "tls"
+	// PhaseConn is wait for connection, including dial of new one.
+	PhaseConn = "conn"
+	// PhaseServer is wait for first response byte after request is written.
+	PhaseServer = "server"
+	// PhaseFirstByte is time from start of request to first response byte.
+	PhaseFirstByte = "first_byte"
+)
+
+// Metrics records client connection lifecycle.
+type Metrics struct {
+	duration    metric.Float64Histogram
+	connections metric.Int64Counter
+}
+
+// NewMetrics creates new Metrics.
+func NewMetrics(meterProvider metric.MeterProvider) (*Metrics, error) {
+	meter := meterProvider.Meter("simon.conntrace")
+	m := &Metrics{}
+	var err error
+	if m.duration, err = meter.Float64Histogram("simon.http.client.phase.duration",
+		metric.WithDescription("Duration of client request phase"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, errors.Wrap(err, "duration")
+	}
+	if m.connections, err = meter.Int64Counter("simon.http.client.connections",
+		metric.WithDescription("Number of connections obtained for requests"),
+	); err != nil {
+		return nil, errors.Wrap(err, "connections")
+	}
+	return m, nil
+}
+
+// request traces single round trip.
+//
+// Hooks may be called concurrently, e.g. for parallel dials.
+type request struct {
+	ctx     context.Context
+	span    trace.Span
+	metrics *Metrics
+	start   time.Time
+
+	mux          sync.Mutex
+	dnsStart     time.Time
+	connectStart map[string]time.Time
+	tlsStart     time.Time
+	wrote        time.Time
+}
+
+func (r *request) phase(name string, d time.Duration, attrs ...attribute.KeyValue) {
+	r.metrics.duration.Record(r.ctx, d.Seconds(), metric.WithAttributes(attribute.String("phase", name)))
+	attrs = append(attrs, attribute.Float64("duration", d.Seconds()))
+	r.span.AddEvent("http."+name, trace.WithAttributes(attrs...))
+}
+
+func errorAttrs(err error) []attribute.KeyValue {
+	if err == nil {
+		return nil
+	}
+	return []attribute.KeyValue{attribute.String("error", err.Error())}
+}
+
+func (r *request) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			r.dnsStart = time.Now()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			attrs := append(errorAttrs(info.Err), attribute.Int("addresses", len(info.Addrs)))
+			r.phase(PhaseDNS, time.Since(r.dnsStart), attrs...)
+		},
+		ConnectStart: func(network, addr string) {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			if r.connectStart == nil {
+				r.connectStart = map[string]time.Time{}
+			}
+			r.connectStart[network+addr] = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			attrs := append(errorAttrs(err), attribute.String("network.peer.address", addr))
+			r.phase(PhaseConnect, time.Since(r.connectStart[network+addr]), attrs...)
+		},
+		TLSHandshakeStart: func() {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			r.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			r.phase(PhaseTLS, time.Since(r.tlsStart), errorAttrs(err)...)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			attrs := []attribute.KeyValue{
+				attribute.Bool("reused", info.Reused),
+				attribute.Bool("was_idle", info.WasIdle),
+			}
+			r.metrics.connections.Add(r.ctx, 1, metric.WithAttributes(attrs...))
+			r.span.SetAttributes(attribute.Bool("http.connection.reused", info.Reused))
+			if info.WasIdle {
+				attrs = append(attrs, attribute.Float64("idle_time", info.IdleTime.Seconds()))
+			}
+			r.phase(PhaseConn, time.Since(r.start), attrs...)
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			r.wrote = time.Now()
+			r.span.AddEvent("http.wrote_request", trace.WithAttributes(errorAttrs(info.Err)...))
+		},
+		GotFirstResponseByte: func() {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+			if !r.wrote.IsZero() {
+				r.phase(PhaseServer, time.Since(r.wrote))
+			}
+			r.phase(PhaseFirstByte, time.Since(r.start))
+		},
+	}
+}
+
+type transport struct {
+	next    http.RoundTripper
+	metrics *Metrics
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	r := &request{
+		ctx:     ctx,
+		span:    trace.SpanFromContext(ctx),
+		metrics: t.metrics,
+		start:   time.Now(),
+	}
+	return t.next.RoundTrip(req.WithContext(httptrace.WithClientTrace(ctx, r.trace())))
+}
+
+// Transport wraps next, recording phases as events of request span
+// and histograms.
+//
+// Should be wrapped by otelhttp, so request span is in context.
+func (m *Metrics) Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next, metrics: m}
+}
@@ -0,0 +1,166 @@
+// Package content generates deterministic content of exact size.
+package content
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/go-faster/errors"
+)
+
+// Kind of content.
+type Kind string
+
+// Kinds of content.
+const (
+	Random Kind = "random"
+	Text   Kind = "text"
+	JSON   Kind = "json"
+)
+
+// ContentType returns media type of kind.
+func (k Kind) ContentType() string {
+	switch k {
+	case Text:
+		return "text/plain; charset=utf-8"
+	case JSON:
+		return "application/json"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+var words = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit",
+	"sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore",
+	"magna", "aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud",
+	"exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea", "commodo",
+}
+
+const blockSize = 4096
+
+// generator appends next piece of content to dst, remaining is number
+// of bytes left to generate.
+type generator func(dst []byte, remaining int64) []byte
+
+type reader struct {
+	remaining int64
+	pending   []byte
+	buf       []byte
+	next      generator
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if len(r.pending) == 0 {
+		r.buf = r.next(r.buf[:0], r.remaining)
+		r.pending = r.buf
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	r.remaining -= int64(n)
+	return n, nil
+}
+
+func randomGenerator(seed int64) generator {
+	var s [32]byte
+	binary.LittleEndian.PutUint64(s[:], uint64(seed)) // #nosec G115
+	src := rand.NewChaCha8(s)
+	return func(dst []byte, _ int64) []byte {
+		if cap(dst) < blockSize {
+			dst = make([]byte, blockSize)
+		}
+		dst = dst[:blockSize]
+		_, _ = src.Read(dst)
+		return dst
+	}
+}
+
+func textGenerator(rnd *rand.Rand) generator {
+	return func(dst []byte, _ int64) []byte {
+		for i := range 12 {
+			if i > 0 {
+				dst = append(dst, ' ')
+			}
+			dst = append(dst, words[rnd.IntN(len(words))]...)
+		}
+		return append(dst, '\n')
+	}
+}
+
+// jsonGenerator generates array of objects, padded with whitespace
+// before closing bracket to match size.
+func jsonGenerator(rnd *rand.Rand) generator {
+	var (
+		started bool
+		id      int
+	)
+	return func(dst []byte, remaining int64) []byte {
+		if !started {
+			started = true
+			return append(dst, '[')
+		}
+		start := len(dst)
+		if id > 0 {
+			dst = append(dst, ',')
+		}
+		dst = fmt.Appendf(dst, `{"id":%d,"word":%s,"value":%d}`,
+			id, strconv.Quote(words[rnd.IntN(len(words))]), rnd.IntN(1000),
+		)
+		if int64(len(dst)-start) < remaining {
+			id++
+			return dst
+		}
+		dst = dst[:start]
+		for i := int64(0); i < min(remaining-1, blockSize); i++ {
+			dst = append(dst, ' ')
+		}
+		if remaining <= blockSize {
+			dst = append(dst, ']')
+		}
+		return dst
+	}
+}
+
+// New returns reader of size bytes of content generated from seed.
+func New(kind Kind, seed, size int64) (io.Reader, error) {
+	if size < 0 {
+		return nil, errors.Errorf("invalid size %d", size)
+	}
+	rnd := rand.New(rand.NewPCG(uint64(seed), 0)) // #nosec G115 G404
+	r := &reader{remaining: size}
+	switch kind {
+	case Random, "":
+		r.next = randomGenerator(seed)
+	case Text:
+		r.next = textGenerator(rnd)
+	case JSON:
+		if size < 2 {
+			return nil, errors.Errorf("json size %d is less than 2", size)
+		}
+		r.next = jsonGenerator(rnd)
+	default:
+		return nil, errors.Errorf("unknown kind %q", kind)
+	}
+	return r, nil
+}
+
+// Range returns reader of length bytes of content starting at offset.
+func Range(kind Kind, seed, size, offset, length int64) (io.Reader, error) {
+	r, err := New(kind, seed, size)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, r, offset); err != nil {
+		return nil, errors.Wrap(err, "skip")
+	}
+	return io.LimitReader(r, length), nil
+}
@@ -0,0 +1,199 @@
+// Package crash implements scenario-driven process failures for restart testing.
+package crash
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-faster/sdk/zctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/simon/internal/middleware"
+)
+
+// Options of failures, zero values disable failure.
+type Options struct {
+	// PanicEvery panics in handler on every N-th request.
+	PanicEvery int
+
+	// ExitAfterRequests exits after N requests.
+	ExitAfterRequests int
+	// ExitAfter exits after duration.
+	ExitAfter time.Duration
+	// ExitCode of exit, defaults to 1 if nil.
+	ExitCode *int
+
+	// DeadlockAfterRequests deadlocks N-th request with lock-order inversion.
+	DeadlockAfterRequests int
+	// DeadlockTimeout is duration of deadlock until detection, defaults to 5s.
+	DeadlockTimeout time.Duration
+
+	// ShutdownHang blocks shutdown for duration, e.g. past grace period.
+	ShutdownHang time.Duration
+
+	// Skip requests, e.g. probes.
+	Skip func(r *http.Request) bool
+}
+
+func (o *Options) setDefaults() {
+	if o.ExitCode == nil {
+		code := 1
+		o.ExitCode = &code
+	}
+	if o.DeadlockTimeout == 0 {
+		o.DeadlockTimeout = 5 * time.Second
+	}
+}
+
+// Flusher flushes telemetry, like SDK providers.
+type Flusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// Crasher triggers failures.
+type Crasher struct {
+	opts     Options
+	tracer   trace.Tracer
+	flushers []Flusher
+	requests atomic.Int64
+
+	// Locks acquired in opposite order to deadlock.
+	first, second sync.Mutex
+}
+
+// New creates new Crasher, flushing telemetry providers before exit.
+// Providers that are not Flusher are ignored.
+func New(opts Options, tracerProvider trace.TracerProvider, providers ...any) *Crasher {
+	opts.setDefaults()
+	c := &Crasher{
+		opts:   opts,
+		tracer: tracerProvider.Tracer("simon.crash"),
+	}
+	for _, p := range append([]any{tracerProvider}, providers...) {
+		if f, ok := p.(Flusher); ok {
+			c.flushers = append(c.flushers, f)
+		}
+	}
+	return c
+}
+
+// Exit emits final log and span, flushes telemetry and exits with code.
+func (c *Crasher) Exit(ctx context.Context, reason string, code int, fields ...zap.Field) {
+	_, span := c.tracer.Start(ctx, "crash.exit", trace.WithAttributes(
+		attribute.String("simon.crash.reason", reason),
+		attribute.Int("simon.crash.exit_code", code),
+	))
+	span.SetStatus(codes.Error, reason)
+	lg := zctx.From(ctx)
+	lg.Error("Exiting", append(fields,
+		zap.String("reason", reason),
+		zap.Int("code", code),
+	)...)
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	for _, f := range c.flushers {
+		_ = f.ForceFlush(ctx)
+	}
+	_ = lg.Sync()
+	os.Exit(code)
+}
+
+// Run triggers time-based failures and detects deadlocks until context is done.
+func (c *Crasher) Run(ctx context.Context) error {
+	var exit <-chan time.Time
+	if c.opts.ExitAfter > 0 {
+		timer := time.NewTimer(c.opts.ExitAfter)
+		defer timer.Stop()
+		exit = timer.C
+	}
+	var watchdog <-chan time.Time
+	if c.opts.DeadlockAfterRequests > 0 {
+		ticker := time.NewTicker(c.opts.DeadlockTimeout / 5)
+		defer ticker.Stop()
+		watchdog = ticker.C
+	}
+	var lockedSince time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-exit:
+			c.Exit(ctx, "timer", *c.opts.ExitCode, zap.Duration("after", c.opts.ExitAfter))
+		case now := <-watchdog:
+			if c.first.TryLock() {
+				c.first.Unlock()
+				lockedSince = time.Time{}
+				continue
+			}
+			if lockedSince.IsZero() {
+				lockedSince = now
+			}
+			if now.Sub(lockedSince) < c.opts.DeadlockTimeout {
+				continue
+			}
+			var dump bytes.Buffer
+			_ = pprof.Lookup("goroutine").WriteTo(&dump, 1)
+			// Same exit code as runtime deadlock detection.
+			c.Exit(ctx, "deadlock", 2, zap.String("goroutines", dump.String()))
+		}
+	}
+}
+
+// HangShutdown blocks for ShutdownHang.
+func (c *Crasher) HangShutdown(ctx context.Context) {
+	if c.opts.ShutdownHang <= 0 {
+		return
+	}
+	zctx.From(ctx).Warn("Hanging shutdown", zap.Duration("duration", c.opts.ShutdownHang))
+	time.Sleep(c.opts.ShutdownHang)
+}
+
+func (c *Crasher) deadlock(ctx context.Context) {
+	zctx.From(ctx).Warn("Deadlocking request")
+	locked := make(chan struct{})
+	go func() {
+		c.second.Lock()
+		close(locked)
+		c.first.Lock()
+	}()
+	c.first.Lock()
+	<-locked
+	c.second.Lock()
+}
+
+// Middleware triggers request-based failures.
+func (c *Crasher) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c.opts.Skip != nil && c.opts.Skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			n := int(c.requests.Add(1))
+			ctx := r.Context()
+			if c.opts.ExitAfterRequests > 0 && n >= c.opts.ExitAfterRequests {
+				c.Exit(ctx, "requests", *c.opts.ExitCode, zap.Int("requests", n))
+			}
+			if c.opts.DeadlockAfterRequests > 0 && n == c.opts.DeadlockAfterRequests {
+				c.deadlock(ctx)
+			}
+			if c.opts.PanicEvery > 0 && n%c.opts.PanicEvery == 0 {
+				zctx.From(ctx).Warn("Panicking", zap.Int("request", n))
+				panic(fmt.Sprintf("simulated panic on request %d", n))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
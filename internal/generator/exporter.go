@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-faster/errors"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+// Exporter sends raw OTLP requests.
+//
+// Unlike SDK exporters, it does not batch, retry or reorder anything,
+// so the generator has full control over what is delivered and when.
+type Exporter interface {
+	ExportTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error
+	ExportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error
+	ExportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error
+	Close() error
+}
+
+// Supported OTLP protocols.
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+)
+
+// NewExporter creates new OTLP exporter for endpoint and protocol.
+//
+// Endpoint is expected in OTEL_EXPORTER_OTLP_ENDPOINT format,
+// e.g. "http://localhost:4317", address without scheme is plain http.
+func NewExporter(endpoint, protocol string) (Exporter, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse endpoint")
+	}
+	switch protocol {
+	case ProtocolGRPC, "":
+		creds := insecure.NewCredentials()
+		if u.Scheme == "https" {
+			creds = credentials.NewClientTLSFromCert(nil, "")
+		}
+		conn, err := grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, errors.Wrap(err, "grpc client")
+		}
+		return &grpcExporter{
+			conn:    conn,
+			traces:  coltracepb.NewTraceServiceClient(conn),
+			metrics: colmetricspb.NewMetricsServiceClient(conn),
+			logs:    collogspb.NewLogsServiceClient(conn),
+		}, nil
+	case ProtocolHTTPProtobuf, "http":
+		return &httpExporter{
+			client:  http.DefaultClient,
+			baseURL: strings.TrimRight(u.String(), "/"),
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown protocol %q", protocol)
+	}
+}
+
+type grpcExporter struct {
+	conn    *grpc.ClientConn
+	traces  coltracepb.TraceServiceClient
+	metrics colmetricspb.MetricsServiceClient
+	logs    collogspb.LogsServiceClient
+}
+
+func (e *grpcExporter) ExportTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	if _, err := e.traces.Export(ctx, req); err != nil {
+		return errors.Wrap(err, "export traces")
+	}
+	return nil
+}
+
+func (e *grpcExporter) ExportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	if _, err := e.metrics.Export(ctx, req); err != nil {
+		return errors.Wrap(err, "export metrics")
+	}
+	return nil
+}
+
+func (e *grpcExporter) ExportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	if _, err := e.logs.Export(ctx, req); err != nil {
+		return errors.Wrap(err, "export logs")
+	}
+	return nil
+}
+
+func (e *grpcExporter) Close() error {
+	return e.conn.Close()
+}
+
+type httpExporter struct {
+	client  *http.Client
+	baseURL string
+}
+
+func (e *httpExporter) post(ctx context.Context, path string, msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "create request")
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := e.client.Do(req) // #nosec G704
+	if err != nil {
+		return errors.Wrap(err, "do request")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.Errorf("%s: status %d: %s", path, resp.StatusCode, body)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (e *httpExporter) ExportTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	return e.post(ctx, "/v1/traces", req)
+}
+
+func (e *httpExporter) ExportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	return e.post(ctx, "/v1/metrics", req)
+}
+
+func (e *httpExporter) ExportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	return e.post(ctx, "/v1/logs", req)
+}
+
+func (e *httpExporter) Close() error {
+	return nil
+}
@@ -0,0 +1,524 @@
+// Package generator implements synthetic OTLP telemetry generator.
+package generator
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"go.uber.org/zap"
+)
+
+// Config of Generator.
+type Config struct {
+	// Services to spread generated telemetry across.
+	Services []string
+	// TraceRate is number of traces generated per second.
+	TraceRate float64
+	// SpansPerTrace is number of spans in each trace.
+	SpansPerTrace int
+	// BatchInterval is interval between export batches.
+	BatchInterval time.Duration
+
+	Disorder Disorder
+}
+
+// Disorder configures deliberately imperfect delivery.
+//
+// Ratios are probabilities in [0, 1], zero disables the behavior.
+type Disorder struct {
+	// LateRatio is probability of trace having a leaf span
+	// delivered LateDelay after its parents were exported.
+	LateRatio float64
+	LateDelay time.Duration
+	// SplitRatio is probability of trace spans being split across
+	// SplitBatches export batches, SplitDelay apart.
+	SplitRatio   float64
+	SplitBatches int
+	SplitDelay   time.Duration
+	// DuplicateRatio is probability of export batch being sent
+	// again after DuplicateDelay.
+	DuplicateRatio float64
+	DuplicateDelay time.Duration
+	// PastRatio is probability of metric point timestamp being
+	// shifted PastSkew into the past.
+	PastRatio float64
+	PastSkew  time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if len(c.Services) == 0 {
+		c.Services = []string{"simon.gen"}
+	}
+	if c.TraceRate <= 0 {
+		c.TraceRate = 1
+	}
+	if c.SpansPerTrace <= 0 {
+		c.SpansPerTrace = 5
+	}
+	if c.BatchInterval <= 0 {
+		c.BatchInterval = time.Second
+	}
+	if c.Disorder.SplitBatches <= 1 {
+		c.Disorder.SplitBatches = 2
+	}
+}
+
+type pendingSpan struct {
+	sendAt  time.Time
+	service string
+	span    *tracepb.Span
+}
+
+type pendingLog struct {
+	service string
+	record  *logspb.LogRecord
+}
+
+type pendingRequest struct {
+	sendAt  time.Time
+	traces  *coltracepb.ExportTraceServiceRequest
+	metrics *colmetricspb.ExportMetricsServiceRequest
+	logs    *collogspb.ExportLogsServiceRequest
+}
+
+// Attributes of generated telemetry.
+const (
+	// AttrGeneratorID is resource attribute with generator instance id.
+	AttrGeneratorID = "simon.generator.id"
+	// AttrSeq is per-signal sequence number of span, metric point or
+	// log record, starting from zero for each generator instance.
+	//
+	// Metric points carry it in exemplar filtered attributes, keeping
+	// series stable.
+	//
+	// Duplicates re-use sequence numbers, so receiver can compute exact
+	// loss and duplication.
+	AttrSeq = "simon.seq"
+	// AttrSpanCount is root span attribute with expected number of spans
+	// in trace.
+	AttrSpanCount = "simon.trace.span_count"
+	// AttrTopology is root span attribute with expected trace topology:
+	// comma-separated parent index of each span, -1 for root.
+	AttrTopology = "simon.trace.topology"
+	// AttrSpanIndex is index of span in trace topology.
+	AttrSpanIndex = "simon.span.index"
+)
+
+// Signal names.
+const (
+	SignalTraces  = "traces"
+	SignalMetrics = "metrics"
+	SignalLogs    = "logs"
+)
+
+// Generator generates traces, metrics and logs and exports them
+// according to Disorder.
+type Generator struct {
+	exp Exporter
+	cfg Config
+	rnd *rand.Rand
+	id  string
+
+	start      time.Time
+	traceDebt  float64
+	pending    []pendingSpan
+	logs       []pendingLog
+	duplicates []pendingRequest
+	requests   map[string]int64
+	seq        map[string]int64
+
+	spans    metric.Int64Counter
+	batches  metric.Int64Counter
+	disorder metric.Int64Counter
+}
+
+// New initializes and returns new Generator.
+func New(exp Exporter, meterProvider metric.MeterProvider, cfg Config) (*Generator, error) {
+	cfg.setDefaults()
+	meter := meterProvider.Meter("simon.generator")
+	g := &Generator{
+		exp:      exp,
+		cfg:      cfg,
+		rnd:      rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404
+		requests: map[string]int64{},
+		seq:      map[string]int64{},
+	}
+	g.id = fmt.Sprintf("%016x", g.rnd.Uint64())
+
+	var err error
+	if g.spans, err = meter.Int64Counter("simon.generator.spans",
+		metric.WithDescription("Number of generated spans"),
+	); err != nil {
+		return nil, errors.Wrap(err, "spans counter")
+	}
+	if g.batches, err = meter.Int64Counter("simon.generator.batches",
+		metric.WithDescription("Number of exported batches"),
+	); err != nil {
+		return nil, errors.Wrap(err, "batches counter")
+	}
+	if g.disorder, err = meter.Int64Counter("simon.generator.disorder",
+		metric.WithDescription("Number of injected delivery anomalies"),
+	); err != nil {
+		return nil, errors.Wrap(err, "disorder counter")
+	}
+
+	return g, nil
+}
+
+// Run generator until context is done.
+func (g *Generator) Run(ctx context.Context) error {
+	g.start = time.Now()
+	ticker := time.NewTicker(g.cfg.BatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			g.tick(ctx, now)
+		}
+	}
+}
+
+func (g *Generator) chance(ratio float64) bool {
+	return ratio > 0 && g.rnd.Float64() < ratio
+}
+
+func (g *Generator) randomID(n int) []byte {
+	id := make([]byte, n)
+	_, _ = g.rnd.Read(id)
+	return id
+}
+
+// nextSeq returns sequence number attribute for signal.
+func (g *Generator) nextSeq(signal string) *commonpb.KeyValue {
+	v := g.seq[signal]
+	g.seq[signal]++
+	return intAttr(AttrSeq, v)
+}
+
+// seqExemplar returns exemplars with metric point sequence number.
+func (g *Generator) seqExemplar(ts uint64) []*metricspb.Exemplar {
+	return []*metricspb.Exemplar{{
+		TimeUnixNano:       ts,
+		FilteredAttributes: []*commonpb.KeyValue{g.nextSeq(SignalMetrics)},
+	}}
+}
+
+func (g *Generator) tick(ctx context.Context, now time.Time) {
+	g.traceDebt += g.cfg.TraceRate * g.cfg.BatchInterval.Seconds()
+	for ; g.traceDebt >= 1; g.traceDebt-- {
+		g.generateTrace(now)
+	}
+
+	g.flushSpans(ctx, now)
+	g.flushLogs(ctx, now)
+	g.flushMetrics(ctx, now)
+	g.flushDuplicates(ctx, now)
+}
+
+func (g *Generator) generateTrace(now time.Time) {
+	var (
+		d       = g.cfg.Disorder
+		n       = g.cfg.SpansPerTrace
+		traceID = g.randomID(16)
+		spans   = make([]*tracepb.Span, n)
+		service = make([]string, n)
+		parents = make([]string, n)
+	)
+
+	root := g.cfg.Services[g.rnd.Intn(len(g.cfg.Services))]
+	rootEnd := now.Add(-time.Duration(g.rnd.Intn(50)) * time.Millisecond)
+	rootStart := rootEnd.Add(-time.Duration(50+g.rnd.Intn(200)) * time.Millisecond)
+	for i := range spans {
+		s := &tracepb.Span{
+			TraceId: traceID,
+			SpanId:  g.randomID(8),
+			Name:    fmt.Sprintf("op.%d", i),
+			Kind:    tracepb.Span_SPAN_KIND_INTERNAL,
+			Attributes: []*commonpb.KeyValue{
+				g.nextSeq(SignalTraces),
+				intAttr(AttrSpanIndex, int64(i)),
+			},
+		}
+		service[i] = root
+		parents[i] = "-1"
+		if i == 0 {
+			s.Kind = tracepb.Span_SPAN_KIND_SERVER
+			s.StartTimeUnixNano = uint64(rootStart.UnixNano())
+			s.EndTimeUnixNano = uint64(rootEnd.UnixNano())
+		} else {
+			// Parent always precedes child, so the last span is a leaf.
+			parentIdx := g.rnd.Intn(i)
+			parent := spans[parentIdx]
+			parents[i] = strconv.Itoa(parentIdx)
+			s.ParentSpanId = parent.SpanId
+			if g.rnd.Intn(3) == 0 {
+				service[i] = g.cfg.Services[g.rnd.Intn(len(g.cfg.Services))]
+			}
+			window := parent.EndTimeUnixNano - parent.StartTimeUnixNano
+			s.StartTimeUnixNano = parent.StartTimeUnixNano + uint64(g.rnd.Int63n(int64(window/2)+1))
+			s.EndTimeUnixNano = s.StartTimeUnixNano + uint64(g.rnd.Int63n(int64(parent.EndTimeUnixNano-s.StartTimeUnixNano)+1))
+		}
+		spans[i] = s
+	}
+	spans[0].Attributes = append(spans[0].Attributes,
+		intAttr(AttrSpanCount, int64(n)),
+		stringAttr(AttrTopology, strings.Join(parents, ",")),
+	)
+
+	sendAt := make([]time.Time, n)
+	for i := range sendAt {
+		sendAt[i] = now
+	}
+	if n > 1 && g.chance(d.SplitRatio) {
+		g.disorder.Add(context.Background(), 1, metric.WithAttributes(attribute.String("kind", "split")))
+		for i := range sendAt {
+			sendAt[i] = sendAt[i].Add(time.Duration(i%d.SplitBatches) * d.SplitDelay)
+		}
+	}
+	if n > 1 && g.chance(d.LateRatio) {
+		g.disorder.Add(context.Background(), 1, metric.WithAttributes(attribute.String("kind", "late")))
+		sendAt[n-1] = sendAt[n-1].Add(d.LateDelay)
+	}
+
+	for i, s := range spans {
+		g.pending = append(g.pending, pendingSpan{
+			sendAt:  sendAt[i],
+			service: service[i],
+			span:    s,
+		})
+	}
+	g.requests[root]++
+	g.logs = append(g.logs, pendingLog{
+		service: root,
+		record: &logspb.LogRecord{
+			TimeUnixNano:   spans[0].EndTimeUnixNano,
+			SeverityNumber: logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+			SeverityText:   "INFO",
+			Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "Request handled"}},
+			TraceId:        traceID,
+			SpanId:         spans[0].SpanId,
+			Attributes: []*commonpb.KeyValue{
+				g.nextSeq(SignalLogs),
+			},
+		},
+	})
+	g.spans.Add(context.Background(), int64(n))
+}
+
+func (g *Generator) resource(service string) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Attributes: []*commonpb.KeyValue{
+			stringAttr("service.name", service),
+			stringAttr(AttrGeneratorID, g.id),
+		},
+	}
+}
+
+func (g *Generator) scope() *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{Name: "simon.generator"}
+}
+
+func (g *Generator) flushSpans(ctx context.Context, now time.Time) {
+	var (
+		due     = map[string][]*tracepb.Span{}
+		order   []string
+		waiting = g.pending[:0]
+	)
+	for _, p := range g.pending {
+		if p.sendAt.After(now) {
+			waiting = append(waiting, p)
+			continue
+		}
+		if _, ok := due[p.service]; !ok {
+			order = append(order, p.service)
+		}
+		due[p.service] = append(due[p.service], p.span)
+	}
+	g.pending = waiting
+	if len(order) == 0 {
+		return
+	}
+
+	req := &coltracepb.ExportTraceServiceRequest{}
+	for _, service := range order {
+		req.ResourceSpans = append(req.ResourceSpans, &tracepb.ResourceSpans{
+			Resource: g.resource(service),
+			ScopeSpans: []*tracepb.ScopeSpans{
+				{Scope: g.scope(), Spans: due[service]},
+			},
+		})
+	}
+	g.export(ctx, now, pendingRequest{traces: req}, false)
+}
+
+func (g *Generator) flushLogs(ctx context.Context, now time.Time) {
+	if len(g.logs) == 0 {
+		return
+	}
+	var (
+		due   = map[string][]*logspb.LogRecord{}
+		order []string
+	)
+	for _, l := range g.logs {
+		if _, ok := due[l.service]; !ok {
+			order = append(order, l.service)
+		}
+		due[l.service] = append(due[l.service], l.record)
+	}
+	g.logs = g.logs[:0]
+
+	req := &collogspb.ExportLogsServiceRequest{}
+	for _, service := range order {
+		req.ResourceLogs = append(req.ResourceLogs, &logspb.ResourceLogs{
+			Resource: g.resource(service),
+			ScopeLogs: []*logspb.ScopeLogs{
+				{Scope: g.scope(), LogRecords: due[service]},
+			},
+		})
+	}
+	g.export(ctx, now, pendingRequest{logs: req}, false)
+}
+
+func (g *Generator) flushMetrics(ctx context.Context, now time.Time) {
+	d := g.cfg.Disorder
+	timestamp := func() uint64 {
+		if g.chance(d.PastRatio) {
+			g.disorder.Add(ctx, 1, metric.WithAttributes(attribute.String("kind", "past")))
+			return uint64(now.Add(-d.PastSkew).UnixNano())
+		}
+		return uint64(now.UnixNano())
+	}
+
+	// Cumulative points must not precede start time of their series.
+	start := uint64(g.start.UnixNano())
+	cumulative := func() uint64 {
+		return max(timestamp(), start)
+	}
+
+	req := &colmetricspb.ExportMetricsServiceRequest{}
+	for _, service := range g.cfg.Services {
+		sumTime, gaugeTime := cumulative(), timestamp()
+		req.ResourceMetrics = append(req.ResourceMetrics, &metricspb.ResourceMetrics{
+			Resource: g.resource(service),
+			ScopeMetrics: []*metricspb.ScopeMetrics{
+				{
+					Scope: g.scope(),
+					Metrics: []*metricspb.Metric{
+						{
+							Name: "simon.generator.requests",
+							Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+								AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+								IsMonotonic:            true,
+								DataPoints: []*metricspb.NumberDataPoint{{
+									StartTimeUnixNano: start,
+									TimeUnixNano:      sumTime,
+									Value:             &metricspb.NumberDataPoint_AsInt{AsInt: g.requests[service]},
+									Exemplars:         g.seqExemplar(sumTime),
+								}},
+							}},
+						},
+						{
+							Name: "simon.generator.queue",
+							Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{
+								DataPoints: []*metricspb.NumberDataPoint{{
+									TimeUnixNano: gaugeTime,
+									Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: float64(len(g.pending))},
+									Exemplars:    g.seqExemplar(gaugeTime),
+								}},
+							}},
+						},
+					},
+				},
+			},
+		})
+	}
+	g.export(ctx, now, pendingRequest{metrics: req}, false)
+}
+
+func (g *Generator) flushDuplicates(ctx context.Context, now time.Time) {
+	var due []pendingRequest
+	waiting := g.duplicates[:0]
+	for _, r := range g.duplicates {
+		if r.sendAt.After(now) {
+			waiting = append(waiting, r)
+			continue
+		}
+		due = append(due, r)
+	}
+	g.duplicates = waiting
+	for _, r := range due {
+		g.export(ctx, now, r, true)
+	}
+}
+
+func (g *Generator) export(ctx context.Context, now time.Time, r pendingRequest, duplicate bool) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	var (
+		signal string
+		err    error
+	)
+	switch {
+	case r.traces != nil:
+		signal = SignalTraces
+		err = g.exp.ExportTraces(ctx, r.traces)
+	case r.metrics != nil:
+		signal = SignalMetrics
+		err = g.exp.ExportMetrics(ctx, r.metrics)
+	case r.logs != nil:
+		signal = SignalLogs
+		err = g.exp.ExportLogs(ctx, r.logs)
+	}
+	if err != nil {
+		zctx.From(ctx).Warn("Export failed",
+			zap.String("signal", signal),
+			zap.Bool("duplicate", duplicate),
+			zap.Error(err),
+		)
+		return
+	}
+	g.batches.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("signal", signal),
+		attribute.Bool("duplicate", duplicate),
+	))
+
+	if !duplicate && g.chance(g.cfg.Disorder.DuplicateRatio) {
+		g.disorder.Add(ctx, 1, metric.WithAttributes(attribute.String("kind", "duplicate")))
+		r.sendAt = now.Add(g.cfg.Disorder.DuplicateDelay)
+		g.duplicates = append(g.duplicates, r)
+	}
+}
+
+func stringAttr(k, v string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   k,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}},
+	}
+}
+
+func intAttr(k string, v int64) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   k,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v}},
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+type flushKey struct{}
+
+// Flusher puts flush of response into request context, so handlers without
+// access to http.ResponseWriter, e.g. generated ones, can stream responses.
+func Flusher() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rc := http.NewResponseController(w)
+			ctx := context.WithValue(r.Context(), flushKey{}, rc.Flush)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Flush flushes response of request context, if any.
+func Flush(ctx context.Context) error {
+	if f, ok := ctx.Value(flushKey{}).(func() error); ok {
+		return f()
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BodyLimit limits request body to n bytes, zero disables limit.
+func BodyLimit(n int64) Middleware {
+	if n <= 0 {
+		return pass
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns IP of request remote address.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ConcurrencyLimit limits in-flight requests per client, rejecting
+// excess with 429. Client is identified by key, ClientIP if nil.
+// Zero disables limit.
+func ConcurrencyLimit(n int, key func(r *http.Request) string) Middleware {
+	if n <= 0 {
+		return pass
+	}
+	if key == nil {
+		key = ClientIP
+	}
+	var (
+		mux      sync.Mutex
+		inflight = map[string]int{}
+	)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k := key(r)
+			mux.Lock()
+			if inflight[k] >= n {
+				mux.Unlock()
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			inflight[k]++
+			mux.Unlock()
+			defer func() {
+				mux.Lock()
+				if inflight[k]--; inflight[k] == 0 {
+					delete(inflight, k)
+				}
+				mux.Unlock()
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Timeout sets deadline on request context, zero disables timeout.
+func Timeout(d time.Duration) Middleware {
+	if d <= 0 {
+		return pass
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
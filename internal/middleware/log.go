@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-faster/sdk/zctx"
+	"go.uber.org/zap"
+)
+
+// responseWriter records status and size of response.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.written += int64(n)
+	return n, err
+}
+
+// Unwrap is used by http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Log writes access log with zctx logger.
+func Log() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+			if rw.status == 0 {
+				rw.status = http.StatusOK
+			}
+			zctx.From(r.Context()).Info("Request",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("proto", r.Proto),
+				zap.String("remote", r.RemoteAddr),
+				zap.Int("status", rw.status),
+				zap.Int64("size", rw.written),
+				zap.Duration("duration", time.Since(start)),
+			)
+		})
+	}
+}
@@ -10,3 +10,40 @@ type Middleware func(next http.Handler) http.Handler
 func Wrap(h http.Handler, mw Middleware) http.Handler {
 	return mw(h)
 }
+
+// Chain of middlewares, first one is outermost.
+type Chain []Middleware
+
+// Append returns new chain with middlewares appended.
+func (c Chain) Append(mws ...Middleware) Chain {
+	return append(c[:len(c):len(c)], mws...)
+}
+
+// Then wraps handler with chain.
+func (c Chain) Then(h http.Handler) http.Handler {
+	for i := len(c) - 1; i >= 0; i-- {
+		if c[i] != nil {
+			h = c[i](h)
+		}
+	}
+	return h
+}
+
+func pass(next http.Handler) http.Handler { return next }
+
+// Unless applies mw to requests that skip does not match, e.g. probes.
+func Unless(skip func(r *http.Request) bool, mw Middleware) Middleware {
+	if mw == nil {
+		return nil
+	}
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+// Code generated by ogen, DO NOT EDIT.
+package oas
+
+type DownloadRes interface {
+	downloadRes()
+}
+
+type HealthzRes interface {
+	healthzRes()
+}
+
+type ReadyzRes interface {
+	readyzRes()
+}
+
+type StartupzRes interface {
+	startupzRes()
+}
@@ -6,6 +6,18 @@ package oas
 type OperationName = string
 
 const (
+	DelayOperation      OperationName = "Delay"
+	DownloadOperation   OperationName = "Download"
+	EchoDeleteOperation OperationName = "EchoDelete"
+	EchoGetOperation    OperationName = "EchoGet"
+	EchoPatchOperation  OperationName = "EchoPatch"
+	EchoPostOperation   OperationName = "EchoPost"
+	EchoPutOperation    OperationName = "EchoPut"
+	HeadersOperation    OperationName = "Headers"
+	HealthzOperation    OperationName = "Healthz"
+	ReadyzOperation     OperationName = "Readyz"
+	StartupzOperation   OperationName = "Startupz"
 	StatusOperation     OperationName = "Status"
+	StatusCodeOperation OperationName = "StatusCode"
 	UploadFileOperation OperationName = "UploadFile"
 )
@@ -10,6 +10,20 @@ import (
 	"github.com/ogen-go/ogen/uri"
 )
 
+var (
+	rn4AllowedHeaders = map[string]string{
+		"GET": "Range",
+	}
+	rn6AllowedHeaders = map[string]string{
+		"PATCH": "Content-Type",
+		"POST":  "Content-Type",
+		"PUT":   "Content-Type",
+	}
+	rn16AllowedHeaders = map[string]string{
+		"POST": "Authorization,Content-Type,X-Api-Key",
+	}
+)
+
 func (s *Server) cutPrefix(path string) (string, bool) {
 	prefix := s.cfg.Prefix
 	if prefix == "" {
@@ -40,6 +54,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.notFound(w, r)
 		return
 	}
+	args := [1]string{}
 
 	// Static code generated router with unwrapped path search.
 	switch {
@@ -60,9 +75,181 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				break
 			}
 			switch elem[0] {
-			case 's': // Prefix: "status"
+			case 'd': // Prefix: "d"
+
+				if l := len("d"); len(elem) >= l && elem[0:l] == "d" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'e': // Prefix: "elay/"
+
+					if l := len("elay/"); len(elem) >= l && elem[0:l] == "elay/" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					// Param: "ms"
+					// Leaf parameter, slashes are prohibited
+					idx := strings.IndexByte(elem, '/')
+					if idx >= 0 {
+						break
+					}
+					args[0] = elem
+					elem = ""
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleDelayRequest([1]string{
+								args[0],
+							}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, notAllowedParams{
+								allowedMethods: "GET",
+								allowedHeaders: nil,
+								acceptPost:     "",
+								acceptPatch:    "",
+							})
+						}
+
+						return
+					}
+
+				case 'o': // Prefix: "ownload"
+
+					if l := len("ownload"); len(elem) >= l && elem[0:l] == "ownload" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleDownloadRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, notAllowedParams{
+								allowedMethods: "GET",
+								allowedHeaders: rn4AllowedHeaders,
+								acceptPost:     "",
+								acceptPatch:    "",
+							})
+						}
+
+						return
+					}
+
+				}
+
+			case 'e': // Prefix: "echo"
+
+				if l := len("echo"); len(elem) >= l && elem[0:l] == "echo" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					// Leaf node.
+					switch r.Method {
+					case "DELETE":
+						s.handleEchoDeleteRequest([0]string{}, elemIsEscaped, w, r)
+					case "GET":
+						s.handleEchoGetRequest([0]string{}, elemIsEscaped, w, r)
+					case "PATCH":
+						s.handleEchoPatchRequest([0]string{}, elemIsEscaped, w, r)
+					case "POST":
+						s.handleEchoPostRequest([0]string{}, elemIsEscaped, w, r)
+					case "PUT":
+						s.handleEchoPutRequest([0]string{}, elemIsEscaped, w, r)
+					default:
+						s.notAllowed(w, r, notAllowedParams{
+							allowedMethods: "DELETE,GET,PATCH,POST,PUT",
+							allowedHeaders: rn6AllowedHeaders,
+							acceptPost:     "*/*",
+							acceptPatch:    "*/*",
+						})
+					}
+
+					return
+				}
+
+			case 'h': // Prefix: "hea"
+
+				if l := len("hea"); len(elem) >= l && elem[0:l] == "hea" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'd': // Prefix: "ders"
+
+					if l := len("ders"); len(elem) >= l && elem[0:l] == "ders" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleHeadersRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, notAllowedParams{
+								allowedMethods: "GET",
+								allowedHeaders: nil,
+								acceptPost:     "",
+								acceptPatch:    "",
+							})
+						}
+
+						return
+					}
+
+				case 'l': // Prefix: "lthz"
 
-				if l := len("status"); len(elem) >= l && elem[0:l] == "status" {
+					if l := len("lthz"); len(elem) >= l && elem[0:l] == "lthz" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleHealthzRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, notAllowedParams{
+								allowedMethods: "GET",
+								allowedHeaders: nil,
+								acceptPost:     "",
+								acceptPatch:    "",
+							})
+						}
+
+						return
+					}
+
+				}
+
+			case 'r': // Prefix: "readyz"
+
+				if l := len("readyz"); len(elem) >= l && elem[0:l] == "readyz" {
 					elem = elem[l:]
 				} else {
 					break
@@ -72,14 +259,120 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 					// Leaf node.
 					switch r.Method {
 					case "GET":
-						s.handleStatusRequest([0]string{}, elemIsEscaped, w, r)
+						s.handleReadyzRequest([0]string{}, elemIsEscaped, w, r)
 					default:
-						s.notAllowed(w, r, "GET")
+						s.notAllowed(w, r, notAllowedParams{
+							allowedMethods: "GET",
+							allowedHeaders: nil,
+							acceptPost:     "",
+							acceptPatch:    "",
+						})
 					}
 
 					return
 				}
 
+			case 's': // Prefix: "sta"
+
+				if l := len("sta"); len(elem) >= l && elem[0:l] == "sta" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'r': // Prefix: "rtupz"
+
+					if l := len("rtupz"); len(elem) >= l && elem[0:l] == "rtupz" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleStartupzRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, notAllowedParams{
+								allowedMethods: "GET",
+								allowedHeaders: nil,
+								acceptPost:     "",
+								acceptPatch:    "",
+							})
+						}
+
+						return
+					}
+
+				case 't': // Prefix: "tus"
+
+					if l := len("tus"); len(elem) >= l && elem[0:l] == "tus" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						switch r.Method {
+						case "GET":
+							s.handleStatusRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, notAllowedParams{
+								allowedMethods: "GET",
+								allowedHeaders: nil,
+								acceptPost:     "",
+								acceptPatch:    "",
+							})
+						}
+
+						return
+					}
+					switch elem[0] {
+					case '/': // Prefix: "/"
+
+						if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						// Param: "code"
+						// Leaf parameter, slashes are prohibited
+						idx := strings.IndexByte(elem, '/')
+						if idx >= 0 {
+							break
+						}
+						args[0] = elem
+						elem = ""
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "GET":
+								s.handleStatusCodeRequest([1]string{
+									args[0],
+								}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, notAllowedParams{
+									allowedMethods: "GET",
+									allowedHeaders: nil,
+									acceptPost:     "",
+									acceptPatch:    "",
+								})
+							}
+
+							return
+						}
+
+					}
+
+				}
+
 			case 'u': // Prefix: "upload"
 
 				if l := len("upload"); len(elem) >= l && elem[0:l] == "upload" {
@@ -94,7 +387,12 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 					case "POST":
 						s.handleUploadFileRequest([0]string{}, elemIsEscaped, w, r)
 					default:
-						s.notAllowed(w, r, "POST")
+						s.notAllowed(w, r, notAllowedParams{
+							allowedMethods: "POST",
+							allowedHeaders: rn16AllowedHeaders,
+							acceptPost:     "multipart/form-data",
+							acceptPatch:    "",
+						})
 					}
 
 					return
@@ -109,12 +407,13 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // Route is route object.
 type Route struct {
-	name        string
-	summary     string
-	operationID string
-	pathPattern string
-	count       int
-	args        [0]string
+	name           string
+	summary        string
+	operationID    string
+	operationGroup string
+	pathPattern    string
+	count          int
+	args           [1]string
 }
 
 // Name returns ogen operation name.
@@ -134,6 +433,11 @@ func (r Route) OperationID() string {
 	return r.operationID
 }
 
+// OperationGroup returns the x-ogen-operation-group value.
+func (r Route) OperationGroup() string {
+	return r.operationGroup
+}
+
 // PathPattern returns OpenAPI path.
 func (r Route) PathPattern() string {
 	return r.pathPattern
@@ -194,9 +498,207 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 				break
 			}
 			switch elem[0] {
-			case 's': // Prefix: "status"
+			case 'd': // Prefix: "d"
 
-				if l := len("status"); len(elem) >= l && elem[0:l] == "status" {
+				if l := len("d"); len(elem) >= l && elem[0:l] == "d" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'e': // Prefix: "elay/"
+
+					if l := len("elay/"); len(elem) >= l && elem[0:l] == "elay/" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					// Param: "ms"
+					// Leaf parameter, slashes are prohibited
+					idx := strings.IndexByte(elem, '/')
+					if idx >= 0 {
+						break
+					}
+					args[0] = elem
+					elem = ""
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = DelayOperation
+							r.summary = ""
+							r.operationID = "delay"
+							r.operationGroup = ""
+							r.pathPattern = "/delay/{ms}"
+							r.args = args
+							r.count = 1
+							return r, true
+						default:
+							return
+						}
+					}
+
+				case 'o': // Prefix: "ownload"
+
+					if l := len("ownload"); len(elem) >= l && elem[0:l] == "ownload" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = DownloadOperation
+							r.summary = ""
+							r.operationID = "download"
+							r.operationGroup = ""
+							r.pathPattern = "/download"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+				}
+
+			case 'e': // Prefix: "echo"
+
+				if l := len("echo"); len(elem) >= l && elem[0:l] == "echo" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					// Leaf node.
+					switch method {
+					case "DELETE":
+						r.name = EchoDeleteOperation
+						r.summary = ""
+						r.operationID = "echoDelete"
+						r.operationGroup = ""
+						r.pathPattern = "/echo"
+						r.args = args
+						r.count = 0
+						return r, true
+					case "GET":
+						r.name = EchoGetOperation
+						r.summary = ""
+						r.operationID = "echoGet"
+						r.operationGroup = ""
+						r.pathPattern = "/echo"
+						r.args = args
+						r.count = 0
+						return r, true
+					case "PATCH":
+						r.name = EchoPatchOperation
+						r.summary = ""
+						r.operationID = "echoPatch"
+						r.operationGroup = ""
+						r.pathPattern = "/echo"
+						r.args = args
+						r.count = 0
+						return r, true
+					case "POST":
+						r.name = EchoPostOperation
+						r.summary = ""
+						r.operationID = "echoPost"
+						r.operationGroup = ""
+						r.pathPattern = "/echo"
+						r.args = args
+						r.count = 0
+						return r, true
+					case "PUT":
+						r.name = EchoPutOperation
+						r.summary = ""
+						r.operationID = "echoPut"
+						r.operationGroup = ""
+						r.pathPattern = "/echo"
+						r.args = args
+						r.count = 0
+						return r, true
+					default:
+						return
+					}
+				}
+
+			case 'h': // Prefix: "hea"
+
+				if l := len("hea"); len(elem) >= l && elem[0:l] == "hea" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'd': // Prefix: "ders"
+
+					if l := len("ders"); len(elem) >= l && elem[0:l] == "ders" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = HeadersOperation
+							r.summary = ""
+							r.operationID = "headers"
+							r.operationGroup = ""
+							r.pathPattern = "/headers"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+				case 'l': // Prefix: "lthz"
+
+					if l := len("lthz"); len(elem) >= l && elem[0:l] == "lthz" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = HealthzOperation
+							r.summary = ""
+							r.operationID = "healthz"
+							r.operationGroup = ""
+							r.pathPattern = "/healthz"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+				}
+
+			case 'r': // Prefix: "readyz"
+
+				if l := len("readyz"); len(elem) >= l && elem[0:l] == "readyz" {
 					elem = elem[l:]
 				} else {
 					break
@@ -206,10 +708,11 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 					// Leaf node.
 					switch method {
 					case "GET":
-						r.name = StatusOperation
+						r.name = ReadyzOperation
 						r.summary = ""
-						r.operationID = "status"
-						r.pathPattern = "/status"
+						r.operationID = "readyz"
+						r.operationGroup = ""
+						r.pathPattern = "/readyz"
 						r.args = args
 						r.count = 0
 						return r, true
@@ -218,6 +721,105 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 					}
 				}
 
+			case 's': // Prefix: "sta"
+
+				if l := len("sta"); len(elem) >= l && elem[0:l] == "sta" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case 'r': // Prefix: "rtupz"
+
+					if l := len("rtupz"); len(elem) >= l && elem[0:l] == "rtupz" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = StartupzOperation
+							r.summary = ""
+							r.operationID = "startupz"
+							r.operationGroup = ""
+							r.pathPattern = "/startupz"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+				case 't': // Prefix: "tus"
+
+					if l := len("tus"); len(elem) >= l && elem[0:l] == "tus" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						switch method {
+						case "GET":
+							r.name = StatusOperation
+							r.summary = ""
+							r.operationID = "status"
+							r.operationGroup = ""
+							r.pathPattern = "/status"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+					switch elem[0] {
+					case '/': // Prefix: "/"
+
+						if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						// Param: "code"
+						// Leaf parameter, slashes are prohibited
+						idx := strings.IndexByte(elem, '/')
+						if idx >= 0 {
+							break
+						}
+						args[0] = elem
+						elem = ""
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "GET":
+								r.name = StatusCodeOperation
+								r.summary = ""
+								r.operationID = "statusCode"
+								r.operationGroup = ""
+								r.pathPattern = "/status/{code}"
+								r.args = args
+								r.count = 1
+								return r, true
+							default:
+								return
+							}
+						}
+
+					}
+
+				}
+
 			case 'u': // Prefix: "upload"
 
 				if l := len("upload"); len(elem) >= l && elem[0:l] == "upload" {
@@ -233,6 +835,7 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 						r.name = UploadFileOperation
 						r.summary = ""
 						r.operationID = "uploadFile"
+						r.operationGroup = ""
 						r.pathPattern = "/upload"
 						r.args = args
 						r.count = 0
@@ -4,7 +4,9 @@ package oas
 
 import (
 	"fmt"
+	"io"
 
+	"github.com/go-faster/errors"
 	ht "github.com/ogen-go/ogen/http"
 )
 
@@ -12,6 +14,509 @@ func (s *ErrorStatusCode) Error() string {
 	return fmt.Sprintf("code %d: %+v", s.StatusCode, s.Response)
 }
 
+type ApiKey struct {
+	APIKey string
+	Roles  []string
+}
+
+// GetAPIKey returns the value of APIKey.
+func (s *ApiKey) GetAPIKey() string {
+	return s.APIKey
+}
+
+// GetRoles returns the value of Roles.
+func (s *ApiKey) GetRoles() []string {
+	return s.Roles
+}
+
+// SetAPIKey sets the value of APIKey.
+func (s *ApiKey) SetAPIKey(val string) {
+	s.APIKey = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *ApiKey) SetRoles(val []string) {
+	s.Roles = val
+}
+
+type BearerAuth struct {
+	Token string
+	Roles []string
+}
+
+// GetToken returns the value of Token.
+func (s *BearerAuth) GetToken() string {
+	return s.Token
+}
+
+// GetRoles returns the value of Roles.
+func (s *BearerAuth) GetRoles() []string {
+	return s.Roles
+}
+
+// SetToken sets the value of Token.
+func (s *BearerAuth) SetToken(val string) {
+	s.Token = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *BearerAuth) SetRoles(val []string) {
+	s.Roles = val
+}
+
+// Ref: #/components/schemas/ContentKind
+type ContentKind string
+
+const (
+	ContentKindRandom ContentKind = "random"
+	ContentKindText   ContentKind = "text"
+	ContentKindJSON   ContentKind = "json"
+)
+
+// AllValues returns all ContentKind values.
+func (ContentKind) AllValues() []ContentKind {
+	return []ContentKind{
+		ContentKindRandom,
+		ContentKindText,
+		ContentKindJSON,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s ContentKind) MarshalText() ([]byte, error) {
+	switch s {
+	case ContentKindRandom:
+		return []byte(s), nil
+	case ContentKindText:
+		return []byte(s), nil
+	case ContentKindJSON:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *ContentKind) UnmarshalText(data []byte) error {
+	switch ContentKind(data) {
+	case ContentKindRandom:
+		*s = ContentKindRandom
+		return nil
+	case ContentKindText:
+		*s = ContentKindText
+		return nil
+	case ContentKindJSON:
+		*s = ContentKindJSON
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/Delay
+type Delay struct {
+	// Requested delay in seconds.
+	Delay float64 `json:"delay"`
+	// Actual delay in seconds.
+	Elapsed float64 `json:"elapsed"`
+}
+
+// GetDelay returns the value of Delay.
+func (s *Delay) GetDelay() float64 {
+	return s.Delay
+}
+
+// GetElapsed returns the value of Elapsed.
+func (s *Delay) GetElapsed() float64 {
+	return s.Elapsed
+}
+
+// SetDelay sets the value of Delay.
+func (s *Delay) SetDelay(val float64) {
+	s.Delay = val
+}
+
+// SetElapsed sets the value of Elapsed.
+func (s *Delay) SetElapsed(val float64) {
+	s.Elapsed = val
+}
+
+type Download struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s Download) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+type DownloadEncoding string
+
+const (
+	DownloadEncodingIdentity DownloadEncoding = "identity"
+	DownloadEncodingGzip     DownloadEncoding = "gzip"
+	DownloadEncodingZstd     DownloadEncoding = "zstd"
+)
+
+// AllValues returns all DownloadEncoding values.
+func (DownloadEncoding) AllValues() []DownloadEncoding {
+	return []DownloadEncoding{
+		DownloadEncodingIdentity,
+		DownloadEncodingGzip,
+		DownloadEncodingZstd,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s DownloadEncoding) MarshalText() ([]byte, error) {
+	switch s {
+	case DownloadEncodingIdentity:
+		return []byte(s), nil
+	case DownloadEncodingGzip:
+		return []byte(s), nil
+	case DownloadEncodingZstd:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *DownloadEncoding) UnmarshalText(data []byte) error {
+	switch DownloadEncoding(data) {
+	case DownloadEncodingIdentity:
+		*s = DownloadEncodingIdentity
+		return nil
+	case DownloadEncodingGzip:
+		*s = DownloadEncodingGzip
+		return nil
+	case DownloadEncodingZstd:
+		*s = DownloadEncodingZstd
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// DownloadHeaders wraps Download with response headers.
+type DownloadHeaders struct {
+	AcceptRanges    OptString
+	ContentEncoding OptString
+	ContentLength   OptInt64
+	ContentRange    OptString
+	ContentType     string
+	Response        Download
+}
+
+// GetAcceptRanges returns the value of AcceptRanges.
+func (s *DownloadHeaders) GetAcceptRanges() OptString {
+	return s.AcceptRanges
+}
+
+// GetContentEncoding returns the value of ContentEncoding.
+func (s *DownloadHeaders) GetContentEncoding() OptString {
+	return s.ContentEncoding
+}
+
+// GetContentLength returns the value of ContentLength.
+func (s *DownloadHeaders) GetContentLength() OptInt64 {
+	return s.ContentLength
+}
+
+// GetContentRange returns the value of ContentRange.
+func (s *DownloadHeaders) GetContentRange() OptString {
+	return s.ContentRange
+}
+
+// GetContentType returns the value of ContentType.
+func (s *DownloadHeaders) GetContentType() string {
+	return s.ContentType
+}
+
+// GetResponse returns the value of Response.
+func (s *DownloadHeaders) GetResponse() Download {
+	return s.Response
+}
+
+// SetAcceptRanges sets the value of AcceptRanges.
+func (s *DownloadHeaders) SetAcceptRanges(val OptString) {
+	s.AcceptRanges = val
+}
+
+// SetContentEncoding sets the value of ContentEncoding.
+func (s *DownloadHeaders) SetContentEncoding(val OptString) {
+	s.ContentEncoding = val
+}
+
+// SetContentLength sets the value of ContentLength.
+func (s *DownloadHeaders) SetContentLength(val OptInt64) {
+	s.ContentLength = val
+}
+
+// SetContentRange sets the value of ContentRange.
+func (s *DownloadHeaders) SetContentRange(val OptString) {
+	s.ContentRange = val
+}
+
+// SetContentType sets the value of ContentType.
+func (s *DownloadHeaders) SetContentType(val string) {
+	s.ContentType = val
+}
+
+// SetResponse sets the value of Response.
+func (s *DownloadHeaders) SetResponse(val Download) {
+	s.Response = val
+}
+
+type DownloadOK DownloadHeaders
+
+func (*DownloadOK) downloadRes() {}
+
+type DownloadPartialContent DownloadHeaders
+
+func (*DownloadPartialContent) downloadRes() {}
+
+// Ref: #/components/schemas/Echo
+type Echo struct {
+	Method     string       `json:"method"`
+	Host       string       `json:"host"`
+	Path       string       `json:"path"`
+	Query      string       `json:"query"`
+	Proto      string       `json:"proto"`
+	RemoteAddr string       `json:"remote_addr"`
+	Headers    HeaderValues `json:"headers"`
+	// Request body, if valid UTF-8.
+	Body OptString `json:"body"`
+	// Request body, if not valid UTF-8.
+	BodyBase64 []byte `json:"body_base64"`
+	BodySize   int64  `json:"body_size"`
+}
+
+// GetMethod returns the value of Method.
+func (s *Echo) GetMethod() string {
+	return s.Method
+}
+
+// GetHost returns the value of Host.
+func (s *Echo) GetHost() string {
+	return s.Host
+}
+
+// GetPath returns the value of Path.
+func (s *Echo) GetPath() string {
+	return s.Path
+}
+
+// GetQuery returns the value of Query.
+func (s *Echo) GetQuery() string {
+	return s.Query
+}
+
+// GetProto returns the value of Proto.
+func (s *Echo) GetProto() string {
+	return s.Proto
+}
+
+// GetRemoteAddr returns the value of RemoteAddr.
+func (s *Echo) GetRemoteAddr() string {
+	return s.RemoteAddr
+}
+
+// GetHeaders returns the value of Headers.
+func (s *Echo) GetHeaders() HeaderValues {
+	return s.Headers
+}
+
+// GetBody returns the value of Body.
+func (s *Echo) GetBody() OptString {
+	return s.Body
+}
+
+// GetBodyBase64 returns the value of BodyBase64.
+func (s *Echo) GetBodyBase64() []byte {
+	return s.BodyBase64
+}
+
+// GetBodySize returns the value of BodySize.
+func (s *Echo) GetBodySize() int64 {
+	return s.BodySize
+}
+
+// SetMethod sets the value of Method.
+func (s *Echo) SetMethod(val string) {
+	s.Method = val
+}
+
+// SetHost sets the value of Host.
+func (s *Echo) SetHost(val string) {
+	s.Host = val
+}
+
+// SetPath sets the value of Path.
+func (s *Echo) SetPath(val string) {
+	s.Path = val
+}
+
+// SetQuery sets the value of Query.
+func (s *Echo) SetQuery(val string) {
+	s.Query = val
+}
+
+// SetProto sets the value of Proto.
+func (s *Echo) SetProto(val string) {
+	s.Proto = val
+}
+
+// SetRemoteAddr sets the value of RemoteAddr.
+func (s *Echo) SetRemoteAddr(val string) {
+	s.RemoteAddr = val
+}
+
+// SetHeaders sets the value of Headers.
+func (s *Echo) SetHeaders(val HeaderValues) {
+	s.Headers = val
+}
+
+// SetBody sets the value of Body.
+func (s *Echo) SetBody(val OptString) {
+	s.Body = val
+}
+
+// SetBodyBase64 sets the value of BodyBase64.
+func (s *Echo) SetBodyBase64(val []byte) {
+	s.BodyBase64 = val
+}
+
+// SetBodySize sets the value of BodySize.
+func (s *Echo) SetBodySize(val int64) {
+	s.BodySize = val
+}
+
+type EchoPatchReq struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s EchoPatchReq) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+// EchoPatchReqWithContentType wraps EchoPatchReq with Content-Type.
+type EchoPatchReqWithContentType struct {
+	ContentType string
+	Content     EchoPatchReq
+}
+
+// GetContentType returns the value of ContentType.
+func (s *EchoPatchReqWithContentType) GetContentType() string {
+	return s.ContentType
+}
+
+// GetContent returns the value of Content.
+func (s *EchoPatchReqWithContentType) GetContent() EchoPatchReq {
+	return s.Content
+}
+
+// SetContentType sets the value of ContentType.
+func (s *EchoPatchReqWithContentType) SetContentType(val string) {
+	s.ContentType = val
+}
+
+// SetContent sets the value of Content.
+func (s *EchoPatchReqWithContentType) SetContent(val EchoPatchReq) {
+	s.Content = val
+}
+
+type EchoPostReq struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s EchoPostReq) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+// EchoPostReqWithContentType wraps EchoPostReq with Content-Type.
+type EchoPostReqWithContentType struct {
+	ContentType string
+	Content     EchoPostReq
+}
+
+// GetContentType returns the value of ContentType.
+func (s *EchoPostReqWithContentType) GetContentType() string {
+	return s.ContentType
+}
+
+// GetContent returns the value of Content.
+func (s *EchoPostReqWithContentType) GetContent() EchoPostReq {
+	return s.Content
+}
+
+// SetContentType sets the value of ContentType.
+func (s *EchoPostReqWithContentType) SetContentType(val string) {
+	s.ContentType = val
+}
+
+// SetContent sets the value of Content.
+func (s *EchoPostReqWithContentType) SetContent(val EchoPostReq) {
+	s.Content = val
+}
+
+type EchoPutReq struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s EchoPutReq) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+// EchoPutReqWithContentType wraps EchoPutReq with Content-Type.
+type EchoPutReqWithContentType struct {
+	ContentType string
+	Content     EchoPutReq
+}
+
+// GetContentType returns the value of ContentType.
+func (s *EchoPutReqWithContentType) GetContentType() string {
+	return s.ContentType
+}
+
+// GetContent returns the value of Content.
+func (s *EchoPutReqWithContentType) GetContent() EchoPutReq {
+	return s.Content
+}
+
+// SetContentType sets the value of ContentType.
+func (s *EchoPutReqWithContentType) SetContentType(val string) {
+	s.ContentType = val
+}
+
+// SetContent sets the value of Content.
+func (s *EchoPutReqWithContentType) SetContent(val EchoPutReq) {
+	s.Content = val
+}
+
 // Error description.
 // Ref: #/components/schemas/Error
 type Error struct {
@@ -54,6 +559,349 @@ func (s *ErrorStatusCode) SetResponse(val Error) {
 	s.Response = val
 }
 
+// Ref: #/components/schemas/HeaderValues
+type HeaderValues map[string][]string
+
+func (s *HeaderValues) init() HeaderValues {
+	m := *s
+	if m == nil {
+		m = map[string][]string{}
+		*s = m
+	}
+	return m
+}
+
+// Ref: #/components/schemas/Headers
+type Headers struct {
+	Headers HeaderValues `json:"headers"`
+	// Trace ID of server span, same as client if trace context is propagated.
+	TraceID string `json:"trace_id"`
+	// Span ID of server span.
+	SpanID string `json:"span_id"`
+	// Span ID of remote parent, if any.
+	ParentSpanID OptString `json:"parent_span_id"`
+	Sampled      bool      `json:"sampled"`
+	// Received baggage members.
+	Baggage HeadersBaggage `json:"baggage"`
+}
+
+// GetHeaders returns the value of Headers.
+func (s *Headers) GetHeaders() HeaderValues {
+	return s.Headers
+}
+
+// GetTraceID returns the value of TraceID.
+func (s *Headers) GetTraceID() string {
+	return s.TraceID
+}
+
+// GetSpanID returns the value of SpanID.
+func (s *Headers) GetSpanID() string {
+	return s.SpanID
+}
+
+// GetParentSpanID returns the value of ParentSpanID.
+func (s *Headers) GetParentSpanID() OptString {
+	return s.ParentSpanID
+}
+
+// GetSampled returns the value of Sampled.
+func (s *Headers) GetSampled() bool {
+	return s.Sampled
+}
+
+// GetBaggage returns the value of Baggage.
+func (s *Headers) GetBaggage() HeadersBaggage {
+	return s.Baggage
+}
+
+// SetHeaders sets the value of Headers.
+func (s *Headers) SetHeaders(val HeaderValues) {
+	s.Headers = val
+}
+
+// SetTraceID sets the value of TraceID.
+func (s *Headers) SetTraceID(val string) {
+	s.TraceID = val
+}
+
+// SetSpanID sets the value of SpanID.
+func (s *Headers) SetSpanID(val string) {
+	s.SpanID = val
+}
+
+// SetParentSpanID sets the value of ParentSpanID.
+func (s *Headers) SetParentSpanID(val OptString) {
+	s.ParentSpanID = val
+}
+
+// SetSampled sets the value of Sampled.
+func (s *Headers) SetSampled(val bool) {
+	s.Sampled = val
+}
+
+// SetBaggage sets the value of Baggage.
+func (s *Headers) SetBaggage(val HeadersBaggage) {
+	s.Baggage = val
+}
+
+// Received baggage members.
+type HeadersBaggage map[string]string
+
+func (s *HeadersBaggage) init() HeadersBaggage {
+	m := *s
+	if m == nil {
+		m = map[string]string{}
+		*s = m
+	}
+	return m
+}
+
+// Ref: #/components/schemas/Health
+type Health struct {
+	Status HealthStatus  `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+// GetStatus returns the value of Status.
+func (s *Health) GetStatus() HealthStatus {
+	return s.Status
+}
+
+// GetChecks returns the value of Checks.
+func (s *Health) GetChecks() []HealthCheck {
+	return s.Checks
+}
+
+// SetStatus sets the value of Status.
+func (s *Health) SetStatus(val HealthStatus) {
+	s.Status = val
+}
+
+// SetChecks sets the value of Checks.
+func (s *Health) SetChecks(val []HealthCheck) {
+	s.Checks = val
+}
+
+// Ref: #/components/schemas/HealthCheck
+type HealthCheck struct {
+	Name   string            `json:"name"`
+	Status HealthCheckStatus `json:"status"`
+	Error  OptString         `json:"error"`
+}
+
+// GetName returns the value of Name.
+func (s *HealthCheck) GetName() string {
+	return s.Name
+}
+
+// GetStatus returns the value of Status.
+func (s *HealthCheck) GetStatus() HealthCheckStatus {
+	return s.Status
+}
+
+// GetError returns the value of Error.
+func (s *HealthCheck) GetError() OptString {
+	return s.Error
+}
+
+// SetName sets the value of Name.
+func (s *HealthCheck) SetName(val string) {
+	s.Name = val
+}
+
+// SetStatus sets the value of Status.
+func (s *HealthCheck) SetStatus(val HealthCheckStatus) {
+	s.Status = val
+}
+
+// SetError sets the value of Error.
+func (s *HealthCheck) SetError(val OptString) {
+	s.Error = val
+}
+
+type HealthCheckStatus string
+
+const (
+	HealthCheckStatusOk   HealthCheckStatus = "ok"
+	HealthCheckStatusFail HealthCheckStatus = "fail"
+)
+
+// AllValues returns all HealthCheckStatus values.
+func (HealthCheckStatus) AllValues() []HealthCheckStatus {
+	return []HealthCheckStatus{
+		HealthCheckStatusOk,
+		HealthCheckStatusFail,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s HealthCheckStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case HealthCheckStatusOk:
+		return []byte(s), nil
+	case HealthCheckStatusFail:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *HealthCheckStatus) UnmarshalText(data []byte) error {
+	switch HealthCheckStatus(data) {
+	case HealthCheckStatusOk:
+		*s = HealthCheckStatusOk
+		return nil
+	case HealthCheckStatusFail:
+		*s = HealthCheckStatusFail
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+type HealthStatus string
+
+const (
+	HealthStatusOk   HealthStatus = "ok"
+	HealthStatusFail HealthStatus = "fail"
+)
+
+// AllValues returns all HealthStatus values.
+func (HealthStatus) AllValues() []HealthStatus {
+	return []HealthStatus{
+		HealthStatusOk,
+		HealthStatusFail,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s HealthStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case HealthStatusOk:
+		return []byte(s), nil
+	case HealthStatusFail:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *HealthStatus) UnmarshalText(data []byte) error {
+	switch HealthStatus(data) {
+	case HealthStatusOk:
+		*s = HealthStatusOk
+		return nil
+	case HealthStatusFail:
+		*s = HealthStatusFail
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+type HealthzOK Health
+
+func (*HealthzOK) healthzRes() {}
+
+type HealthzServiceUnavailable Health
+
+func (*HealthzServiceUnavailable) healthzRes() {}
+
+// NewOptContentKind returns new OptContentKind with value set to v.
+func NewOptContentKind(v ContentKind) OptContentKind {
+	return OptContentKind{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptContentKind is optional ContentKind.
+type OptContentKind struct {
+	Value ContentKind
+	Set   bool
+}
+
+// IsSet returns true if OptContentKind was set.
+func (o OptContentKind) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptContentKind) Reset() {
+	var v ContentKind
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptContentKind) SetTo(v ContentKind) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptContentKind) Get() (v ContentKind, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptContentKind) Or(d ContentKind) ContentKind {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptDownloadEncoding returns new OptDownloadEncoding with value set to v.
+func NewOptDownloadEncoding(v DownloadEncoding) OptDownloadEncoding {
+	return OptDownloadEncoding{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDownloadEncoding is optional DownloadEncoding.
+type OptDownloadEncoding struct {
+	Value DownloadEncoding
+	Set   bool
+}
+
+// IsSet returns true if OptDownloadEncoding was set.
+func (o OptDownloadEncoding) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDownloadEncoding) Reset() {
+	var v DownloadEncoding
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDownloadEncoding) SetTo(v DownloadEncoding) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDownloadEncoding) Get() (v DownloadEncoding, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDownloadEncoding) Or(d DownloadEncoding) DownloadEncoding {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptInt returns new OptInt with value set to v.
 func NewOptInt(v int) OptInt {
 	return OptInt{
@@ -100,6 +948,114 @@ func (o OptInt) Or(d int) int {
 	return d
 }
 
+// NewOptInt64 returns new OptInt64 with value set to v.
+func NewOptInt64(v int64) OptInt64 {
+	return OptInt64{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInt64 is optional int64.
+type OptInt64 struct {
+	Value int64
+	Set   bool
+}
+
+// IsSet returns true if OptInt64 was set.
+func (o OptInt64) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInt64) Reset() {
+	var v int64
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInt64) SetTo(v int64) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInt64) Get() (v int64, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInt64) Or(d int64) int64 {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
+	return OptString{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
+func (o OptString) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptString) Reset() {
+	var v string
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptString) SetTo(v string) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptString) Get() (v string, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptString) Or(d string) string {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+type ReadyzOK Health
+
+func (*ReadyzOK) readyzRes() {}
+
+type ReadyzServiceUnavailable Health
+
+func (*ReadyzServiceUnavailable) readyzRes() {}
+
+type StartupzOK Health
+
+func (*StartupzOK) startupzRes() {}
+
+type StartupzServiceUnavailable Health
+
+func (*StartupzServiceUnavailable) startupzRes() {}
+
 // Ref: #/components/schemas/Status
 type Status struct {
 	Message string `json:"message"`
@@ -8,12 +8,84 @@ import (
 
 // Handler handles operations described by OpenAPI v3 specification.
 type Handler interface {
+	// Delay implements delay operation.
+	//
+	// Respond after delay.
+	//
+	// GET /delay/{ms}
+	Delay(ctx context.Context, params DelayParams) (*Delay, error)
+	// Download implements download operation.
+	//
+	// Download generated content, same seed produces same content.
+	//
+	// GET /download
+	Download(ctx context.Context, params DownloadParams) (DownloadRes, error)
+	// EchoDelete implements echoDelete operation.
+	//
+	// Reflect DELETE request.
+	//
+	// DELETE /echo
+	EchoDelete(ctx context.Context) (*Echo, error)
+	// EchoGet implements echoGet operation.
+	//
+	// Reflect GET request.
+	//
+	// GET /echo
+	EchoGet(ctx context.Context) (*Echo, error)
+	// EchoPatch implements echoPatch operation.
+	//
+	// Reflect PATCH request.
+	//
+	// PATCH /echo
+	EchoPatch(ctx context.Context, req *EchoPatchReqWithContentType) (*Echo, error)
+	// EchoPost implements echoPost operation.
+	//
+	// Reflect POST request.
+	//
+	// POST /echo
+	EchoPost(ctx context.Context, req *EchoPostReqWithContentType) (*Echo, error)
+	// EchoPut implements echoPut operation.
+	//
+	// Reflect PUT request.
+	//
+	// PUT /echo
+	EchoPut(ctx context.Context, req *EchoPutReqWithContentType) (*Echo, error)
+	// Headers implements headers operation.
+	//
+	// Reflect request headers and received trace context.
+	//
+	// GET /headers
+	Headers(ctx context.Context) (*Headers, error)
+	// Healthz implements healthz operation.
+	//
+	// Liveness probe.
+	//
+	// GET /healthz
+	Healthz(ctx context.Context) (HealthzRes, error)
+	// Readyz implements readyz operation.
+	//
+	// Readiness probe, depends on downstream availability.
+	//
+	// GET /readyz
+	Readyz(ctx context.Context) (ReadyzRes, error)
+	// Startupz implements startupz operation.
+	//
+	// Startup probe.
+	//
+	// GET /startupz
+	Startupz(ctx context.Context) (StartupzRes, error)
 	// Status implements status operation.
 	//
 	// Get status.
 	//
 	// GET /status
 	Status(ctx context.Context) (*Status, error)
+	// StatusCode implements statusCode operation.
+	//
+	// Respond with status code.
+	//
+	// GET /status/{code}
+	StatusCode(ctx context.Context, params StatusCodeParams) (*Status, error)
 	// UploadFile implements uploadFile operation.
 	//
 	// Upload a file.
@@ -29,18 +101,20 @@ type Handler interface {
 // Server implements http server based on OpenAPI v3 specification and
 // calls Handler to handle requests.
 type Server struct {
-	h Handler
+	h   Handler
+	sec SecurityHandler
 	baseServer
 }
 
 // NewServer creates new Server.
-func NewServer(h Handler, opts ...ServerOption) (*Server, error) {
+func NewServer(h Handler, sec SecurityHandler, opts ...ServerOption) (*Server, error) {
 	s, err := newServerConfig(opts...).baseServer()
 	if err != nil {
 		return nil, err
 	}
 	return &Server{
 		h:          h,
+		sec:        sec,
 		baseServer: s,
 	}, nil
 }
@@ -13,6 +13,105 @@ type UnimplementedHandler struct{}
 
 var _ Handler = UnimplementedHandler{}
 
+// Delay implements delay operation.
+//
+// Respond after delay.
+//
+// GET /delay/{ms}
+func (UnimplementedHandler) Delay(ctx context.Context, params DelayParams) (r *Delay, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// Download implements download operation.
+//
+// Download generated content, same seed produces same content.
+//
+// GET /download
+func (UnimplementedHandler) Download(ctx context.Context, params DownloadParams) (r DownloadRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EchoDelete implements echoDelete operation.
+//
+// Reflect DELETE request.
+//
+// DELETE /echo
+func (UnimplementedHandler) EchoDelete(ctx context.Context) (r *Echo, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EchoGet implements echoGet operation.
+//
+// Reflect GET request.
+//
+// GET /echo
+func (UnimplementedHandler) EchoGet(ctx context.Context) (r *Echo, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EchoPatch implements echoPatch operation.
+//
+// Reflect PATCH request.
+//
+// PATCH /echo
+func (UnimplementedHandler) EchoPatch(ctx context.Context, req *EchoPatchReqWithContentType) (r *Echo, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EchoPost implements echoPost operation.
+//
+// Reflect POST request.
+//
+// POST /echo
+func (UnimplementedHandler) EchoPost(ctx context.Context, req *EchoPostReqWithContentType) (r *Echo, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EchoPut implements echoPut operation.
+//
+// Reflect PUT request.
+//
+// PUT /echo
+func (UnimplementedHandler) EchoPut(ctx context.Context, req *EchoPutReqWithContentType) (r *Echo, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// Headers implements headers operation.
+//
+// Reflect request headers and received trace context.
+//
+// GET /headers
+func (UnimplementedHandler) Headers(ctx context.Context) (r *Headers, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// Healthz implements healthz operation.
+//
+// Liveness probe.
+//
+// GET /healthz
+func (UnimplementedHandler) Healthz(ctx context.Context) (r HealthzRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// Readyz implements readyz operation.
+//
+// Readiness probe, depends on downstream availability.
+//
+// GET /readyz
+func (UnimplementedHandler) Readyz(ctx context.Context) (r ReadyzRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// Startupz implements startupz operation.
+//
+// Startup probe.
+//
+// GET /startupz
+func (UnimplementedHandler) Startupz(ctx context.Context) (r StartupzRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // Status implements status operation.
 //
 // Get status.
@@ -22,6 +121,15 @@ func (UnimplementedHandler) Status(ctx context.Context) (r *Status, _ error) {
 	return r, ht.ErrNotImplemented
 }
 
+// StatusCode implements statusCode operation.
+//
+// Respond with status code.
+//
+// GET /status/{code}
+func (UnimplementedHandler) StatusCode(ctx context.Context, params StatusCodeParams) (r *Status, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // UploadFile implements uploadFile operation.
 //
 // Upload a file.
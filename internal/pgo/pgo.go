@@ -0,0 +1,112 @@
+// Package pgo collects and merges CPU profiles for profile-guided optimization.
+package pgo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/google/pprof/profile"
+	"golang.org/x/sync/errgroup"
+)
+
+// ProfilePath is path of CPU profile endpoint.
+const ProfilePath = "/debug/pprof/profile"
+
+// profileURL returns CPU profile URL of target for duration.
+//
+// Target is either base URL of pprof server, like http://localhost:9464,
+// or full URL of profile endpoint.
+func profileURL(target string, duration time.Duration) (string, error) {
+	if !strings.Contains(target, "://") {
+		target = "http://" + target
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", errors.Wrap(err, "parse")
+	}
+	if !strings.Contains(u.Path, "/debug/pprof/") {
+		u.Path = strings.TrimRight(u.Path, "/") + ProfilePath
+	}
+	q := u.Query()
+	q.Set("seconds", strconv.Itoa(max(int(duration.Seconds()), 1)))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Fetch CPU profile of target for duration.
+func Fetch(ctx context.Context, client *http.Client, target string, duration time.Duration) (*profile.Profile, error) {
+	u, err := profileURL(target, duration)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "create request")
+	}
+	resp, err := client.Do(req) // #nosec G704
+	if err != nil {
+		return nil, errors.Wrap(err, "do request")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s: status %d", u, resp.StatusCode)
+	}
+	p, err := profile.Parse(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse profile")
+	}
+	return p, nil
+}
+
+// Collect CPU profiles of targets concurrently, so they cover the same period.
+func Collect(ctx context.Context, client *http.Client, targets []string, duration time.Duration) ([]*profile.Profile, error) {
+	profiles := make([]*profile.Profile, len(targets))
+	g, ctx := errgroup.WithContext(ctx)
+	for i, target := range targets {
+		g.Go(func() error {
+			p, err := Fetch(ctx, client, target, duration)
+			if err != nil {
+				return errors.Wrap(err, target)
+			}
+			profiles[i] = p
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// Merge profiles into single compacted profile.
+func Merge(profiles ...*profile.Profile) (*profile.Profile, error) {
+	if len(profiles) == 0 {
+		return nil, errors.New("no profiles")
+	}
+	p, err := profile.Merge(profiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "merge")
+	}
+	return p.Compact(), nil
+}
+
+// Write profile to file.
+func Write(name string, p *profile.Profile) error {
+	f, err := os.Create(name) // #nosec G304
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	if err := p.Write(f); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "write")
+	}
+	return f.Close()
+}
@@ -0,0 +1,278 @@
+// Package probe implements ingestion-to-query latency probe.
+package probe
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/go-faster/simon/internal/generator"
+)
+
+const (
+	// MetricName is name of marker metric, its value is write time of
+	// the last marker in unix milliseconds, so the series is stable.
+	MetricName = "simon_probe_marker"
+	// AttrID is span and log attribute with marker ID.
+	AttrID = "simon.probe.id"
+)
+
+// Marker is uniquely tagged telemetry written by probe.
+type Marker struct {
+	ID      string
+	TraceID []byte
+	SpanID  []byte
+	// Time when marker was written.
+	Time time.Time
+}
+
+// Config of Prober.
+type Config struct {
+	// Service name of marker telemetry.
+	Service string
+	// Interval between probe rounds.
+	Interval time.Duration
+	// PollInterval between query attempts.
+	PollInterval time.Duration
+	// Timeout for marker to become visible.
+	Timeout time.Duration
+	// Checkers by signal name. Signal without checker is not probed.
+	Checkers map[string]Checker
+}
+
+// Prober writes markers and measures time until they are visible.
+type Prober struct {
+	exp generator.Exporter
+	cfg Config
+
+	latency   metric.Float64Histogram
+	failures  metric.Int64Counter
+	freshness metric.Float64Gauge
+}
+
+// New initializes and returns new Prober.
+func New(exp generator.Exporter, meterProvider metric.MeterProvider, cfg Config) (*Prober, error) {
+	if cfg.Service == "" {
+		cfg.Service = "simon.probe"
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second * 30
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Minute * 2
+	}
+	meter := meterProvider.Meter("simon.probe")
+	p := &Prober{
+		exp: exp,
+		cfg: cfg,
+	}
+
+	var err error
+	if p.latency, err = meter.Float64Histogram("simon.probe.latency",
+		metric.WithDescription("Time between writing marker and it becoming visible through query API"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.1, 0.25, 0.5, 1, 2, 5, 10, 15, 30, 60, 120),
+	); err != nil {
+		return nil, errors.Wrap(err, "latency histogram")
+	}
+	if p.failures, err = meter.Int64Counter("simon.probe.failures",
+		metric.WithDescription("Number of markers that did not become visible before timeout"),
+	); err != nil {
+		return nil, errors.Wrap(err, "failures counter")
+	}
+	if p.freshness, err = meter.Float64Gauge("simon.probe.freshness",
+		metric.WithDescription("Latency of the last probe round, or timeout if marker was not found"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, errors.Wrap(err, "freshness gauge")
+	}
+
+	return p, nil
+}
+
+// Run probe rounds until context is done.
+func (p *Prober) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := p.Round(ctx); err != nil {
+			zctx.From(ctx).Error("Probe failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func randomHex(n int) []byte {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return b
+}
+
+// Round writes single marker and waits until it is visible for all signals.
+func (p *Prober) Round(ctx context.Context) error {
+	m := Marker{
+		TraceID: randomHex(16),
+		SpanID:  randomHex(8),
+		Time:    time.Now(),
+	}
+	m.ID = hex.EncodeToString(randomHex(8))
+
+	lg := zctx.From(ctx).With(zap.String("probe_id", m.ID))
+	if err := p.write(ctx, m); err != nil {
+		return errors.Wrap(err, "write marker")
+	}
+	lg.Debug("Marker written")
+
+	g, ctx := errgroup.WithContext(ctx)
+	for signal, c := range p.cfg.Checkers {
+		g.Go(func() error {
+			attrs := metric.WithAttributes(attribute.String("signal", signal))
+			latency, err := p.wait(ctx, c, m)
+			if err != nil {
+				p.failures.Add(ctx, 1, attrs)
+				p.freshness.Record(ctx, p.cfg.Timeout.Seconds(), attrs)
+				lg.Warn("Marker not visible", zap.String("signal", signal), zap.Error(err))
+				return nil
+			}
+			p.latency.Record(ctx, latency.Seconds(), attrs)
+			p.freshness.Record(ctx, latency.Seconds(), attrs)
+			lg.Info("Marker visible",
+				zap.String("signal", signal),
+				zap.Duration("latency", latency),
+			)
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+func (p *Prober) wait(ctx context.Context, c Checker, m Marker) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(p.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		ok, err := c.Visible(ctx, m)
+		if ok {
+			return time.Since(m.Time), nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return 0, errors.Wrap(lastErr, "timeout")
+			}
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func stringAttr(k, v string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   k,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}},
+	}
+}
+
+// write exports marker span, log record and metric point.
+func (p *Prober) write(ctx context.Context, m Marker) error {
+	var (
+		res   = &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttr("service.name", p.cfg.Service)}}
+		scope = &commonpb.InstrumentationScope{Name: "simon.probe"}
+		ts    = uint64(m.Time.UnixNano())
+	)
+
+	if _, ok := p.cfg.Checkers[generator.SignalTraces]; ok {
+		if err := p.exp.ExportTraces(ctx, &coltracepb.ExportTraceServiceRequest{
+			ResourceSpans: []*tracepb.ResourceSpans{{
+				Resource: res,
+				ScopeSpans: []*tracepb.ScopeSpans{{
+					Scope: scope,
+					Spans: []*tracepb.Span{{
+						TraceId:           m.TraceID,
+						SpanId:            m.SpanID,
+						Name:              "probe.marker",
+						Kind:              tracepb.Span_SPAN_KIND_INTERNAL,
+						StartTimeUnixNano: ts,
+						EndTimeUnixNano:   ts,
+						Attributes:        []*commonpb.KeyValue{stringAttr(AttrID, m.ID)},
+					}},
+				}},
+			}},
+		}); err != nil {
+			return errors.Wrap(err, "traces")
+		}
+	}
+	if _, ok := p.cfg.Checkers[generator.SignalLogs]; ok {
+		if err := p.exp.ExportLogs(ctx, &collogspb.ExportLogsServiceRequest{
+			ResourceLogs: []*logspb.ResourceLogs{{
+				Resource: res,
+				ScopeLogs: []*logspb.ScopeLogs{{
+					Scope: scope,
+					LogRecords: []*logspb.LogRecord{{
+						TimeUnixNano:   ts,
+						SeverityNumber: logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+						SeverityText:   "INFO",
+						Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "simon probe marker " + m.ID}},
+						TraceId:        m.TraceID,
+						SpanId:         m.SpanID,
+						Attributes:     []*commonpb.KeyValue{stringAttr(AttrID, m.ID)},
+					}},
+				}},
+			}},
+		}); err != nil {
+			return errors.Wrap(err, "logs")
+		}
+	}
+	if _, ok := p.cfg.Checkers[generator.SignalMetrics]; ok {
+		if err := p.exp.ExportMetrics(ctx, &colmetricspb.ExportMetricsServiceRequest{
+			ResourceMetrics: []*metricspb.ResourceMetrics{{
+				Resource: res,
+				ScopeMetrics: []*metricspb.ScopeMetrics{{
+					Scope: scope,
+					Metrics: []*metricspb.Metric{{
+						Name: MetricName,
+						Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{
+							DataPoints: []*metricspb.NumberDataPoint{{
+								TimeUnixNano: ts,
+								Value:        &metricspb.NumberDataPoint_AsInt{AsInt: m.Time.UnixMilli()},
+							}},
+						}},
+					}},
+				}},
+			}},
+		}); err != nil {
+			return errors.Wrap(err, "metrics")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package probe
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+
+	"github.com/go-faster/simon/internal/generator"
+)
+
+// backend is stand-in for OTLP ingestion and Tempo, Loki and Prometheus
+// query APIs, making written telemetry visible after delay queries.
+type backend struct {
+	mux     sync.Mutex
+	delay   int
+	drop    bool
+	traces  []string
+	logs    []string
+	metric  int64
+	queries map[string]int
+}
+
+var _ generator.Exporter = (*backend)(nil)
+
+func (b *backend) ExportTraces(_ context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.drop {
+		return nil
+	}
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				b.traces = append(b.traces, hex.EncodeToString(span.GetTraceId()))
+			}
+		}
+	}
+	return nil
+}
+
+func (b *backend) ExportMetrics(_ context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.drop {
+		return nil
+	}
+	for _, rm := range req.GetResourceMetrics() {
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				if m.GetName() != MetricName {
+					continue
+				}
+				for _, p := range m.GetGauge().GetDataPoints() {
+					b.metric = p.GetAsInt()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (b *backend) ExportLogs(_ context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.drop {
+		return nil
+	}
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			for _, r := range sl.GetLogRecords() {
+				b.logs = append(b.logs, r.GetBody().GetStringValue())
+			}
+		}
+	}
+	return nil
+}
+
+func (b *backend) Close() error { return nil }
+
+// visible reports whether signal is visible, counting queries.
+func (b *backend) visible(signal string, found func() bool) bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.queries[signal]++
+	return b.queries[signal] > b.delay && found()
+}
+
+func writeResult(w http.ResponseWriter, found bool) {
+	result := []any{}
+	if found {
+		result = append(result, map[string]any{})
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status": "success",
+		"data":   map[string]any{"result": result},
+	})
+}
+
+func (b *backend) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/traces/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if !b.visible(generator.SignalTraces, func() bool { return slices.Contains(b.traces, id) }) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /loki/api/v1/query_range", func(w http.ResponseWriter, r *http.Request) {
+		_, filter, ok := strings.Cut(r.URL.Query().Get("query"), "|= ")
+		if !ok {
+			t.Errorf("unexpected loki query %q", r.URL.Query().Get("query"))
+		}
+		id, err := strconv.Unquote(filter)
+		if err != nil {
+			t.Errorf("loki filter %q: %v", filter, err)
+		}
+		writeResult(w, b.visible(generator.SignalLogs, func() bool {
+			return slices.ContainsFunc(b.logs, func(line string) bool { return strings.Contains(line, id) })
+		}))
+	})
+	mux.HandleFunc("GET /api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+		_, value, ok := strings.Cut(r.URL.Query().Get("query"), MetricName+" >= ")
+		if !ok {
+			t.Errorf("unexpected prometheus query %q", r.URL.Query().Get("query"))
+		}
+		since, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			t.Errorf("prometheus value %q: %v", value, err)
+		}
+		writeResult(w, b.visible(generator.SignalMetrics, func() bool { return b.metric >= since }))
+	})
+	return mux
+}
+
+func newProber(t *testing.T, b *backend, timeout time.Duration) (*Prober, *sdkmetric.ManualReader) {
+	t.Helper()
+	srv := httptest.NewServer(b.handler(t))
+	t.Cleanup(srv.Close)
+
+	reader := sdkmetric.NewManualReader()
+	p, err := New(b, sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), Config{
+		PollInterval: 10 * time.Millisecond,
+		Timeout:      timeout,
+		Checkers: map[string]Checker{
+			generator.SignalTraces:  TempoChecker{Client: srv.Client(), BaseURL: srv.URL},
+			generator.SignalLogs:    LokiChecker{Client: srv.Client(), BaseURL: srv.URL, Selector: `{service_name="simon.probe"}`},
+			generator.SignalMetrics: PrometheusChecker{Client: srv.Client(), BaseURL: srv.URL},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, reader
+}
+
+// collect returns latency histogram counts and failures by signal.
+func collect(t *testing.T, reader *sdkmetric.ManualReader) (latency, failures map[string]int64) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	latency = map[string]int64{}
+	failures = map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, p := range data.DataPoints {
+					signal, _ := p.Attributes.Value("signal")
+					latency[signal.AsString()] += int64(p.Count)
+				}
+			case metricdata.Sum[int64]:
+				for _, p := range data.DataPoints {
+					signal, _ := p.Attributes.Value("signal")
+					failures[signal.AsString()] += p.Value
+				}
+			}
+		}
+	}
+	return latency, failures
+}
+
+func TestProberRound(t *testing.T) {
+	b := &backend{delay: 2, queries: map[string]int{}}
+	p, reader := newProber(t, b, 5*time.Second)
+
+	for range 2 {
+		if err := p.Round(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latency, failures := collect(t, reader)
+	for _, signal := range []string{generator.SignalTraces, generator.SignalLogs, generator.SignalMetrics} {
+		if latency[signal] != 2 {
+			t.Errorf("%s: expected 2 latency observations, got %d", signal, latency[signal])
+		}
+		if failures[signal] != 0 {
+			t.Errorf("%s: expected no failures, got %d", signal, failures[signal])
+		}
+		// Marker is not visible for delay queries of first round, then
+		// second round finds its marker on first query.
+		if got := b.queries[signal]; got != b.delay+2 {
+			t.Errorf("%s: expected %d queries, got %d", signal, b.delay+2, got)
+		}
+	}
+}
+
+func TestProberRoundTimeout(t *testing.T) {
+	b := &backend{drop: true, queries: map[string]int{}}
+	p, reader := newProber(t, b, 50*time.Millisecond)
+
+	if err := p.Round(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	latency, failures := collect(t, reader)
+	for _, signal := range []string{generator.SignalTraces, generator.SignalLogs, generator.SignalMetrics} {
+		if latency[signal] != 0 {
+			t.Errorf("%s: expected no latency observations, got %d", signal, latency[signal])
+		}
+		if failures[signal] != 1 {
+			t.Errorf("%s: expected 1 failure, got %d", signal, failures[signal])
+		}
+	}
+}
+
+func TestPrometheusCheckerStale(t *testing.T) {
+	b := &backend{queries: map[string]int{}}
+	srv := httptest.NewServer(b.handler(t))
+	defer srv.Close()
+
+	c := PrometheusChecker{Client: srv.Client(), BaseURL: srv.URL}
+	now := time.Now()
+	b.metric = now.Add(-time.Minute).UnixMilli()
+
+	visible, err := c.Visible(t.Context(), Marker{Time: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if visible {
+		t.Error("marker of previous round should not be visible")
+	}
+}
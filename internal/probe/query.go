@@ -0,0 +1,127 @@
+package probe
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// Checker checks whether marker is visible through query API.
+type Checker interface {
+	Visible(ctx context.Context, m Marker) (bool, error)
+}
+
+func get(ctx context.Context, client *http.Client, u string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "create request")
+	}
+	resp, err := client.Do(req) // #nosec G704
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "do request")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "read response")
+	}
+	return resp.StatusCode, data, nil
+}
+
+// TempoChecker looks up marker trace by ID using Tempo-compatible API.
+type TempoChecker struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+// Visible implements Checker.
+func (c TempoChecker) Visible(ctx context.Context, m Marker) (bool, error) {
+	u := strings.TrimRight(c.BaseURL, "/") + "/api/traces/" + hex.EncodeToString(m.TraceID)
+	code, data, err := get(ctx, c.Client, u)
+	if err != nil {
+		return false, err
+	}
+	switch code {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, errors.Errorf("tempo: status %d: %s", code, data)
+	}
+}
+
+// resultResponse is common part of Loki and Prometheus query responses.
+type resultResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Result []json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+func parseResult(name string, code int, data []byte) (bool, error) {
+	if code != http.StatusOK {
+		return false, errors.Errorf("%s: status %d: %s", name, code, data)
+	}
+	var r resultResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		return false, errors.Wrapf(err, "%s: decode", name)
+	}
+	if r.Status != "success" {
+		return false, errors.Errorf("%s: status %q", name, r.Status)
+	}
+	return len(r.Data.Result) > 0, nil
+}
+
+// LokiChecker looks up marker log line using Loki query_range API.
+type LokiChecker struct {
+	Client  *http.Client
+	BaseURL string
+	// Selector is LogQL stream selector, e.g. {service_name="simon.probe"}.
+	Selector string
+}
+
+// Visible implements Checker.
+func (c LokiChecker) Visible(ctx context.Context, m Marker) (bool, error) {
+	q := url.Values{}
+	q.Set("query", fmt.Sprintf("%s |= %q", c.Selector, m.ID))
+	q.Set("start", strconv.FormatInt(m.Time.Add(-time.Minute).UnixNano(), 10))
+	q.Set("end", strconv.FormatInt(time.Now().Add(time.Minute).UnixNano(), 10))
+	q.Set("limit", "1")
+	u := strings.TrimRight(c.BaseURL, "/") + "/loki/api/v1/query_range?" + q.Encode()
+	code, data, err := get(ctx, c.Client, u)
+	if err != nil {
+		return false, err
+	}
+	return parseResult("loki", code, data)
+}
+
+// PrometheusChecker looks up marker metric point using Prometheus query API,
+// matching point with value not older than marker.
+type PrometheusChecker struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+// Visible implements Checker.
+func (c PrometheusChecker) Visible(ctx context.Context, m Marker) (bool, error) {
+	q := url.Values{}
+	q.Set("query", fmt.Sprintf("%s >= %d", MetricName, m.Time.UnixMilli()))
+	u := strings.TrimRight(c.BaseURL, "/") + "/api/v1/query?" + q.Encode()
+	code, data, err := get(ctx, c.Client, u)
+	if err != nil {
+		return false, err
+	}
+	return parseResult("prometheus", code, data)
+}
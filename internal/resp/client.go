@@ -0,0 +1,226 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// Client is instrumented RESP client with connection pool.
+type Client struct {
+	addr   string
+	host   string
+	port   int
+	dialer net.Dialer
+	pool   chan *conn
+
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	lookups  metric.Int64Counter
+}
+
+// NewClient creates new RESP client for addr, keeping up to poolSize idle
+// connections.
+func NewClient(addr string, poolSize int, tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Client, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse address")
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	meter := meterProvider.Meter("simon.resp")
+	duration, err := meter.Float64Histogram("simon.resp.duration",
+		metric.WithDescription("Duration of RESP commands"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "duration")
+	}
+	lookups, err := meter.Int64Counter("simon.resp.lookups",
+		metric.WithDescription("Number of GET commands by result"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "lookups")
+	}
+
+	return &Client{
+		addr:     addr,
+		host:     host,
+		port:     port,
+		dialer:   net.Dialer{Timeout: 5 * time.Second},
+		pool:     make(chan *conn, max(poolSize, 1)),
+		tracer:   tracerProvider.Tracer("simon.resp"),
+		duration: duration,
+		lookups:  lookups,
+	}, nil
+}
+
+func (c *Client) acquire(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.pool:
+		return cn, nil
+	default:
+	}
+	nc, err := c.dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "dial")
+	}
+	return &conn{Conn: nc, r: bufio.NewReader(nc)}, nil
+}
+
+func (c *Client) release(cn *conn) {
+	select {
+	case c.pool <- cn:
+	default:
+		_ = cn.Close()
+	}
+}
+
+// Close idle connections.
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.pool:
+			_ = cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) roundTrip(ctx context.Context, args [][]byte) (Value, error) {
+	cn, err := c.acquire(ctx)
+	if err != nil {
+		return Value{}, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	if err := cn.SetDeadline(deadline); err != nil {
+		_ = cn.Close()
+		return Value{}, errors.Wrap(err, "set deadline")
+	}
+	if _, err := cn.Write(appendCommand(nil, args)); err != nil {
+		_ = cn.Close()
+		return Value{}, errors.Wrap(err, "write")
+	}
+	v, err := readValue(cn.r)
+	if err != nil {
+		_ = cn.Close()
+		return Value{}, errors.Wrap(err, "read")
+	}
+	c.release(cn)
+	if v.Type == '-' {
+		return v, Error(v.Str)
+	}
+	return v, nil
+}
+
+// Do executes command, recording CLIENT span.
+//
+// Statement on span only contains command and key, values are omitted.
+func (c *Client) Do(ctx context.Context, args ...[]byte) (Value, error) {
+	if len(args) == 0 {
+		return Value{}, errors.New("empty command")
+	}
+	op := strings.ToUpper(string(args[0]))
+	statement := op
+	if len(args) > 1 {
+		statement += " " + string(args[1])
+	}
+	if len(args) > 2 {
+		statement += " ?"
+	}
+	ctx, span := c.tracer.Start(ctx, op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemRedis,
+			semconv.DBOperation(op),
+			semconv.DBStatement(statement),
+			semconv.ServerAddress(c.host),
+			semconv.ServerPort(c.port),
+			semconv.NetworkTransportTCP,
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	v, err := c.roundTrip(ctx, args)
+	status := "ok"
+	if err != nil {
+		status = "error"
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	c.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		semconv.DBOperation(op),
+		attribute.String("status", status),
+	))
+	return v, err
+}
+
+// Ping checks server availability.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, []byte("PING"))
+	return err
+}
+
+// Get returns value of key and whether it was found.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, err := c.Do(ctx, []byte("GET"), []byte(key))
+	if err != nil {
+		return nil, false, err
+	}
+	result := "hit"
+	if v.Null {
+		result = "miss"
+	}
+	trace.SpanFromContext(ctx).AddEvent("cache "+result, trace.WithAttributes(
+		attribute.String("key", key),
+	))
+	c.lookups.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+	return v.Str, !v.Null, nil
+}
+
+// Set value of key with optional ttl.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := [][]byte{[]byte("SET"), []byte(key), value}
+	if ttl > 0 {
+		args = append(args, []byte("PX"), strconv.AppendInt(nil, ttl.Milliseconds(), 10))
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// Del deletes keys, returning number of deleted ones.
+func (c *Client) Del(ctx context.Context, keys ...string) (int64, error) {
+	args := [][]byte{[]byte("DEL")}
+	for _, k := range keys {
+		args = append(args, []byte(k))
+	}
+	v, err := c.Do(ctx, args...)
+	return v.Int, err
+}
+
+// Expire sets ttl of key, returning false if key does not exist.
+func (c *Client) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	seconds := max(int64(ttl/time.Second), 1)
+	v, err := c.Do(ctx, []byte("EXPIRE"), []byte(key), strconv.AppendInt(nil, seconds, 10))
+	return v.Int == 1, err
+}
@@ -0,0 +1,141 @@
+// Package resp implements minimal Redis serialization protocol (RESP2)
+// server and instrumented client.
+package resp
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+
+	"github.com/go-faster/errors"
+)
+
+// Value is RESP2 value.
+type Value struct {
+	// Type is one of '+', '-', ':', '$', '*'.
+	Type  byte
+	Str   []byte
+	Int   int64
+	Array []Value
+	// Null is set for null bulk string or array.
+	Null bool
+}
+
+// Error is RESP error reply.
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+const maxBulk = 512 * 1024 * 1024
+
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errors.New("bad line terminator")
+	}
+	return line[:len(line)-2], nil
+}
+
+func readValue(r *bufio.Reader) (Value, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return Value{}, err
+	}
+	if len(line) == 0 {
+		return Value{}, errors.New("empty line")
+	}
+	v := Value{Type: line[0]}
+	switch v.Type {
+	case '+', '-':
+		v.Str = append([]byte(nil), line[1:]...)
+		return v, nil
+	case ':':
+		n, err := strconv.ParseInt(string(line[1:]), 10, 64)
+		if err != nil {
+			return Value{}, errors.Wrap(err, "integer")
+		}
+		v.Int = n
+		return v, nil
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return Value{}, errors.Wrap(err, "bulk length")
+		}
+		if n < 0 {
+			v.Null = true
+			return v, nil
+		}
+		if n > maxBulk {
+			return Value{}, errors.Errorf("bulk too big: %d", n)
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return Value{}, errors.Wrap(err, "bulk")
+		}
+		v.Str = buf[:n]
+		return v, nil
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return Value{}, errors.Wrap(err, "array length")
+		}
+		if n < 0 {
+			v.Null = true
+			return v, nil
+		}
+		v.Array = make([]Value, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			e, err := readValue(r)
+			if err != nil {
+				return Value{}, err
+			}
+			v.Array = append(v.Array, e)
+		}
+		return v, nil
+	default:
+		return Value{}, errors.Errorf("unexpected type %q", v.Type)
+	}
+}
+
+func appendBulk(b, s []byte) []byte {
+	b = append(b, '$')
+	b = strconv.AppendInt(b, int64(len(s)), 10)
+	b = append(b, '\r', '\n')
+	b = append(b, s...)
+	return append(b, '\r', '\n')
+}
+
+func appendNull(b []byte) []byte {
+	return append(b, "$-1\r\n"...)
+}
+
+func appendInt(b []byte, n int64) []byte {
+	b = append(b, ':')
+	b = strconv.AppendInt(b, n, 10)
+	return append(b, '\r', '\n')
+}
+
+func appendSimple(b []byte, s string) []byte {
+	b = append(b, '+')
+	b = append(b, s...)
+	return append(b, '\r', '\n')
+}
+
+func appendError(b []byte, s string) []byte {
+	b = append(b, '-')
+	b = append(b, s...)
+	return append(b, '\r', '\n')
+}
+
+func appendCommand(b []byte, args [][]byte) []byte {
+	b = append(b, '*')
+	b = strconv.AppendInt(b, int64(len(args)), 10)
+	b = append(b, '\r', '\n')
+	for _, a := range args {
+		b = appendBulk(b, a)
+	}
+	return b
+}
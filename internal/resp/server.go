@@ -0,0 +1,253 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// Options of Server.
+type Options struct {
+	// Latency is added to every command.
+	Latency time.Duration
+	// Jitter is random additional latency in [0, Jitter).
+	Jitter time.Duration
+	// MissRatio is probability of GET returning nil for existing key,
+	// simulating eviction.
+	MissRatio float64
+}
+
+type entry struct {
+	value   []byte
+	expires time.Time // zero if no expiration
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// Server is in-memory RESP server supporting PING, GET, SET, DEL and EXPIRE.
+type Server struct {
+	opts Options
+
+	mux  sync.Mutex
+	data map[string]entry
+	rnd  *rand.Rand
+}
+
+// NewServer creates new in-memory RESP server.
+func NewServer(opts Options) *Server {
+	return &Server{
+		opts: opts,
+		data: map[string]entry{},
+		rnd:  rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404
+	}
+}
+
+// Serve connections from listener until context is done.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = ln.Close()
+	})
+	defer stop()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.expireLoop(ctx)
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "accept")
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// expireLoop periodically removes expired keys.
+func (s *Server) expireLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mux.Lock()
+			for k, e := range s.data {
+				if e.expired(now) {
+					delete(s.data, k)
+				}
+			}
+			s.mux.Unlock()
+		}
+	}
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var (
+		r   = bufio.NewReader(conn)
+		w   = bufio.NewWriter(conn)
+		out []byte
+	)
+	for {
+		v, err := readValue(r)
+		if err != nil {
+			return
+		}
+		out = s.handle(out[:0], v)
+		if _, err := w.Write(out); err != nil {
+			return
+		}
+		// Flush only when there are no pipelined commands.
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) delay() {
+	d := s.opts.Latency
+	s.mux.Lock()
+	if s.opts.Jitter > 0 {
+		d += time.Duration(s.rnd.Int63n(int64(s.opts.Jitter)))
+	}
+	s.mux.Unlock()
+	if d > 0 {
+		time.Sleep(d)
+	}
+}
+
+func (s *Server) handle(b []byte, v Value) []byte {
+	if v.Type != '*' || len(v.Array) == 0 {
+		return appendError(b, "ERR expected command array")
+	}
+	args := make([][]byte, len(v.Array))
+	for i, a := range v.Array {
+		if a.Type != '$' {
+			return appendError(b, "ERR expected bulk string")
+		}
+		args[i] = a.Str
+	}
+
+	s.delay()
+
+	now := time.Now()
+	switch strings.ToUpper(string(args[0])) {
+	case "PING":
+		if len(args) > 1 {
+			return appendBulk(b, args[1])
+		}
+		return appendSimple(b, "PONG")
+	case "GET":
+		if len(args) != 2 {
+			return appendError(b, "ERR wrong number of arguments for 'get' command")
+		}
+		s.mux.Lock()
+		defer s.mux.Unlock()
+		e, ok := s.data[string(args[1])]
+		if !ok || e.expired(now) {
+			return appendNull(b)
+		}
+		if s.opts.MissRatio > 0 && s.rnd.Float64() < s.opts.MissRatio {
+			delete(s.data, string(args[1]))
+			return appendNull(b)
+		}
+		return appendBulk(b, e.value)
+	case "SET":
+		if len(args) != 3 && len(args) != 5 {
+			return appendError(b, "ERR syntax error")
+		}
+		e := entry{value: append([]byte(nil), args[2]...)}
+		if len(args) == 5 {
+			ttl, err := parseTTL(string(args[3]), string(args[4]))
+			if err != nil {
+				return appendError(b, err.Error())
+			}
+			e.expires = now.Add(ttl)
+		}
+		s.mux.Lock()
+		s.data[string(args[1])] = e
+		s.mux.Unlock()
+		return appendSimple(b, "OK")
+	case "DEL":
+		if len(args) < 2 {
+			return appendError(b, "ERR wrong number of arguments for 'del' command")
+		}
+		var n int64
+		s.mux.Lock()
+		for _, k := range args[1:] {
+			if e, ok := s.data[string(k)]; ok {
+				delete(s.data, string(k))
+				if !e.expired(now) {
+					n++
+				}
+			}
+		}
+		s.mux.Unlock()
+		return appendInt(b, n)
+	case "EXPIRE":
+		if len(args) != 3 {
+			return appendError(b, "ERR wrong number of arguments for 'expire' command")
+		}
+		ttl, err := parseTTL("EX", string(args[2]))
+		if err != nil {
+			return appendError(b, err.Error())
+		}
+		s.mux.Lock()
+		defer s.mux.Unlock()
+		e, ok := s.data[string(args[1])]
+		if !ok || e.expired(now) {
+			return appendInt(b, 0)
+		}
+		e.expires = now.Add(ttl)
+		s.data[string(args[1])] = e
+		return appendInt(b, 1)
+	default:
+		return appendError(b, "ERR unknown command '"+string(args[0])+"'")
+	}
+}
+
+func parseTTL(unit, value string) (time.Duration, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, errors.New("ERR invalid expire time")
+	}
+	switch strings.ToUpper(unit) {
+	case "EX":
+		return time.Duration(n) * time.Second, nil
+	case "PX":
+		return time.Duration(n) * time.Millisecond, nil
+	default:
+		return 0, errors.New("ERR syntax error")
+	}
+}
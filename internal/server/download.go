@@ -0,0 +1,217 @@
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-faster/simon/internal/content"
+	"github.com/go-faster/simon/internal/middleware"
+	"github.com/go-faster/simon/internal/oas"
+)
+
+// DefaultDownloadMaxSize is default limit of Download size.
+const DefaultDownloadMaxSize = 1 << 30
+
+var (
+	// ErrRangeNotSatisfiable is returned if range starts after content end.
+	ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+	// ErrTooLarge is returned if requested size exceeds limit.
+	ErrTooLarge = errors.New("size exceeds limit")
+)
+
+// ParseRange parses single byte range of content of size, reporting false
+// if header is invalid or has multiple ranges and should be ignored.
+func ParseRange(header string, size int64) (start, length int64, ok bool, err error) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false, nil
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false, nil
+	}
+	if first == "" {
+		// Suffix range, last n bytes.
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, false, nil
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, true, ErrRangeNotSatisfiable
+		}
+		n = min(n, size)
+		return size - n, n, true, nil
+	}
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false, nil
+	}
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return 0, 0, false, nil
+		}
+		end = min(end, size-1)
+	}
+	if start >= size {
+		return 0, 0, true, ErrRangeNotSatisfiable
+	}
+	return start, end - start + 1, true, nil
+}
+
+// chunkReader limits reads to chunks, flushing response and waiting
+// before each chunk after first.
+type chunkReader struct {
+	ctx   context.Context
+	r     io.Reader
+	size  int
+	delay time.Duration
+	left  int
+	read  bool
+}
+
+func (c *chunkReader) wait() error {
+	if err := middleware.Flush(c.ctx); err != nil {
+		return errors.Wrap(err, "flush")
+	}
+	if c.delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(c.delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if c.left == 0 {
+		if c.read {
+			if err := c.wait(); err != nil {
+				return 0, err
+			}
+		}
+		c.read = true
+		c.left = c.size
+	}
+	if len(p) > c.left {
+		p = p[:c.left]
+	}
+	n, err := c.r.Read(p)
+	c.left -= n
+	return n, err
+}
+
+func (c *chunkReader) Close() error {
+	if closer, ok := c.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// encode compresses r in background, closing returned reader stops it.
+func encode(r io.Reader, encoding oas.DownloadEncoding) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		var w io.WriteCloser
+		switch encoding {
+		case oas.DownloadEncodingZstd:
+			zw, err := zstd.NewWriter(pw)
+			if err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+			w = zw
+		default:
+			w = gzip.NewWriter(pw)
+		}
+		_, err := io.Copy(w, r)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// Download implements oas.Handler.
+func (s Server) Download(ctx context.Context, params oas.DownloadParams) (oas.DownloadRes, error) {
+	var (
+		kind     = content.Kind(params.Type.Or(oas.ContentKindRandom))
+		size     = params.Size.Or(1 << 20)
+		seed     = params.Seed.Or(0)
+		encoding = params.Encoding.Or(oas.DownloadEncodingIdentity)
+	)
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("simon.download.type", string(kind)),
+		attribute.Int64("simon.download.size", size),
+		attribute.Int64("simon.download.seed", seed),
+	)
+	if size > s.downloadMaxSize {
+		return nil, errors.Wrapf(ErrTooLarge, "size %d, limit %d", size, s.downloadMaxSize)
+	}
+
+	var (
+		start, length = int64(0), size
+		partial       bool
+	)
+	if header, ok := params.Range.Get(); ok {
+		var err error
+		if start, length, partial, err = ParseRange(header, size); err != nil {
+			return nil, errors.Wrapf(err, "range %q of %d bytes", header, size)
+		}
+		if !partial {
+			start, length = 0, size
+		}
+	}
+	r, err := content.Range(kind, seed, size, start, length)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate")
+	}
+
+	headers := oas.DownloadHeaders{
+		AcceptRanges: oas.NewOptString("bytes"),
+		ContentType:  kind.ContentType(),
+	}
+	if partial {
+		// Ranges are of identity content.
+		encoding = oas.DownloadEncodingIdentity
+		headers.ContentRange.SetTo(fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, size))
+		span.SetAttributes(attribute.String("simon.download.range", headers.ContentRange.Value))
+	}
+	span.SetAttributes(attribute.String("simon.download.encoding", string(encoding)))
+	if encoding == oas.DownloadEncodingIdentity {
+		headers.ContentLength.SetTo(length)
+	} else {
+		headers.ContentEncoding.SetTo(string(encoding))
+		r = encode(r, encoding)
+	}
+	if chunk := params.ChunkSize.Or(0); chunk > 0 {
+		r = &chunkReader{
+			ctx:   ctx,
+			r:     r,
+			size:  chunk,
+			delay: time.Duration(params.ChunkDelay.Or(0)) * time.Millisecond,
+		}
+	}
+	headers.Response = oas.Download{Data: r}
+
+	if partial {
+		return (*oas.DownloadPartialContent)(&headers), nil
+	}
+	return (*oas.DownloadOK)(&headers), nil
+}
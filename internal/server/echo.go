@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-faster/errors"
+	"github.com/ogen-go/ogen/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-faster/simon/internal/oas"
+)
+
+type rawRequestKey struct{}
+
+// RawRequest is ogen middleware that passes raw request to handlers,
+// required by diagnostic operations like Echo.
+func RawRequest(req middleware.Request, next middleware.Next) (middleware.Response, error) {
+	req.Context = context.WithValue(req.Context, rawRequestKey{}, req.Raw)
+	return next(req)
+}
+
+func rawRequest(ctx context.Context) (*http.Request, error) {
+	r, ok := ctx.Value(rawRequestKey{}).(*http.Request)
+	if !ok {
+		return nil, errors.New("no raw request, RawRequest middleware is not set")
+	}
+	return r, nil
+}
+
+// echo reflects request of ctx with body.
+func echo(ctx context.Context, body io.Reader) (*oas.Echo, error) {
+	r, err := rawRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e := &oas.Echo{
+		Method:     r.Method,
+		Host:       r.Host,
+		Path:       r.URL.Path,
+		Query:      r.URL.RawQuery,
+		Proto:      r.Proto,
+		RemoteAddr: r.RemoteAddr,
+		Headers:    oas.HeaderValues(r.Header.Clone()),
+	}
+	if body == nil {
+		return e, nil
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "read body")
+	}
+	e.BodySize = int64(len(data))
+	if utf8.Valid(data) {
+		e.Body.SetTo(string(data))
+	} else {
+		e.BodyBase64 = data
+	}
+	return e, nil
+}
+
+// EchoGet implements oas.Handler.
+func (s Server) EchoGet(ctx context.Context) (*oas.Echo, error) {
+	return echo(ctx, nil)
+}
+
+// EchoDelete implements oas.Handler.
+func (s Server) EchoDelete(ctx context.Context) (*oas.Echo, error) {
+	return echo(ctx, nil)
+}
+
+// EchoPost implements oas.Handler.
+func (s Server) EchoPost(ctx context.Context, req *oas.EchoPostReqWithContentType) (*oas.Echo, error) {
+	if req == nil {
+		return echo(ctx, nil)
+	}
+	return echo(ctx, req.Content)
+}
+
+// EchoPut implements oas.Handler.
+func (s Server) EchoPut(ctx context.Context, req *oas.EchoPutReqWithContentType) (*oas.Echo, error) {
+	if req == nil {
+		return echo(ctx, nil)
+	}
+	return echo(ctx, req.Content)
+}
+
+// EchoPatch implements oas.Handler.
+func (s Server) EchoPatch(ctx context.Context, req *oas.EchoPatchReqWithContentType) (*oas.Echo, error) {
+	if req == nil {
+		return echo(ctx, nil)
+	}
+	return echo(ctx, req.Content)
+}
+
+// Delay implements oas.Handler.
+func (s Server) Delay(ctx context.Context, params oas.DelayParams) (*oas.Delay, error) {
+	start := time.Now()
+	d := time.Duration(params.Ms) * time.Millisecond
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &oas.Delay{
+		Delay:   d.Seconds(),
+		Elapsed: time.Since(start).Seconds(),
+	}, nil
+}
+
+// StatusCode implements oas.Handler.
+func (s Server) StatusCode(ctx context.Context, params oas.StatusCodeParams) (*oas.Status, error) {
+	if params.Code == http.StatusOK {
+		return &oas.Status{Message: http.StatusText(params.Code)}, nil
+	}
+	return nil, &oas.ErrorStatusCode{
+		StatusCode: params.Code,
+		Response: oas.Error{
+			Message: http.StatusText(params.Code),
+		},
+	}
+}
+
+// Headers implements oas.Handler.
+func (s Server) Headers(ctx context.Context) (*oas.Headers, error) {
+	r, err := rawRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	h := &oas.Headers{
+		Headers: oas.HeaderValues(r.Header.Clone()),
+		TraceID: sc.TraceID().String(),
+		SpanID:  sc.SpanID().String(),
+		Sampled: sc.IsSampled(),
+		Baggage: oas.HeadersBaggage{},
+	}
+	// Extract again, as received, since server spans are in between.
+	received := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+	if parent := trace.SpanContextFromContext(received); parent.IsValid() {
+		h.ParentSpanID.SetTo(parent.SpanID().String())
+	}
+	for _, m := range baggage.FromContext(received).Members() {
+		h.Baggage[m.Key()] = m.Value()
+	}
+	return h, nil
+}
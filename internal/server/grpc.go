@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/go-faster/sdk/zctx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-faster/simon/internal/simonpb"
+)
+
+// maxUploadSize limits size of gRPC upload.
+const maxUploadSize = 64 * 1024 * 1024
+
+// grpcServer implements simonpb.SimonServiceServer.
+type grpcServer struct {
+	simonpb.UnimplementedSimonServiceServer
+	s *Server
+}
+
+func (g grpcServer) Status(ctx context.Context, _ *simonpb.StatusRequest) (*simonpb.StatusResponse, error) {
+	ctx, span := g.s.trace.Start(ctx, "Server.Status")
+	defer span.End()
+	zctx.From(ctx).Info("Status")
+	return &simonpb.StatusResponse{Message: "ok"}, nil
+}
+
+func (g grpcServer) UploadFile(stream grpc.ClientStreamingServer[simonpb.UploadFileRequest, simonpb.UploadFileResponse]) error {
+	ctx, span := g.s.trace.Start(stream.Context(), "Server.UploadFile")
+	defer span.End()
+
+	var (
+		buf        = new(bytes.Buffer)
+		iterations = 1
+		first      = true
+	)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first && req.GetIterations() > 0 {
+			iterations = int(req.GetIterations())
+		}
+		first = false
+		if buf.Len()+len(req.GetChunk()) > maxUploadSize {
+			return status.Error(codes.ResourceExhausted, "upload is too big")
+		}
+		buf.Write(req.GetChunk())
+	}
+
+	hash, err := g.s.upload(ctx, buf.Bytes(), iterations)
+	if err != nil {
+		zctx.From(ctx).Error("Upload failed", zap.Error(err))
+		return status.Error(codes.Internal, err.Error())
+	}
+	return stream.SendAndClose(&simonpb.UploadFileResponse{Hash: hash})
+}
+
+type loggerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s loggerStream) Context() context.Context { return s.ctx }
+
+// LoggerInterceptors returns interceptors that set base logger of request context,
+// like http.Server.BaseContext does for HTTP.
+func LoggerInterceptors(lg *zap.Logger) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(zctx.Base(ctx, lg), req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, loggerStream{ServerStream: ss, ctx: zctx.Base(ss.Context(), lg)})
+	}
+	return unary, stream
+}
+
+// RegisterGRPC registers gRPC API on server.
+func (s *Server) RegisterGRPC(srv *grpc.Server) {
+	simonpb.RegisterSimonServiceServer(srv, grpcServer{s: s})
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-faster/simon/internal/oas"
+)
+
+// Flap periodically fails probe for Down duration of every Period.
+type Flap struct {
+	Period time.Duration
+	Down   time.Duration
+}
+
+// failing reports whether probe should fail after elapsed time since start.
+func (f Flap) failing(elapsed time.Duration) bool {
+	if f.Period <= 0 || f.Down <= 0 {
+		return false
+	}
+	return elapsed%f.Period >= f.Period-f.Down
+}
+
+// HealthOptions configures probes.
+type HealthOptions struct {
+	// StartupDelay is duration after start when startup and readiness
+	// probes fail, simulating slow startup.
+	StartupDelay time.Duration
+	// LivenessFlap and ReadinessFlap simulate flapping probes.
+	LivenessFlap  Flap
+	ReadinessFlap Flap
+	// CheckTimeout is timeout of single downstream check.
+	CheckTimeout time.Duration
+	// CheckExternal enables readiness checks of external downstream
+	// hosts, otherwise only in-cluster dependencies are checked.
+	CheckExternal bool
+}
+
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func checkURL(uri string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, uri, http.NoBody)
+		if err != nil {
+			return errors.Wrap(err, "create request")
+		}
+		resp, err := http.DefaultClient.Do(req) // #nosec G704
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return errors.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// healthChecks returns checks of downstream dependencies that are
+// actually reachable over network.
+func (s Server) healthChecks() []healthCheck {
+	var checks []healthCheck
+	for _, step := range s.downstream {
+		if !s.health.CheckExternal {
+			// Internet blips should not make pods unready.
+			break
+		}
+		switch step {
+		case DownstreamExternal:
+			checks = append(checks, healthCheck{name: step, check: checkURL(s.getEnvDefault("EXTERNAL_URL", "https://www.google.com/"))})
+		case DownstreamCurl:
+			checks = append(checks, healthCheck{name: step, check: checkURL(s.getEnvDefault("CURL_URL", "https://ifconfig.me"))})
+		}
+	}
+	if s.cache != nil {
+		checks = append(checks, healthCheck{name: "cache", check: s.cache.Ping})
+	}
+	if s.broker != nil {
+		checks = append(checks, healthCheck{name: "broker", check: s.broker.Ping})
+	}
+	return checks
+}
+
+func (s Server) runChecks(ctx context.Context) (oas.Health, bool) {
+	checks := s.healthChecks()
+	results := make([]oas.HealthCheck, len(checks))
+
+	timeout := s.health.CheckTimeout
+	if timeout <= 0 {
+		timeout = 500 * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = oas.HealthCheck{Name: c.name, Status: oas.HealthCheckStatusOk}
+			if err := c.check(ctx); err != nil {
+				results[i].Status = oas.HealthCheckStatusFail
+				results[i].Error = oas.NewOptString(err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+
+	ok := true
+	for _, r := range results {
+		if r.Status != oas.HealthCheckStatusOk {
+			ok = false
+		}
+	}
+	return healthStatus(ok, results...), ok
+}
+
+func healthStatus(ok bool, checks ...oas.HealthCheck) oas.Health {
+	h := oas.Health{Status: oas.HealthStatusOk, Checks: checks}
+	if !ok {
+		h.Status = oas.HealthStatusFail
+	}
+	return h
+}
+
+func probeCheck(name string, ok bool, reason string) oas.HealthCheck {
+	c := oas.HealthCheck{Name: name, Status: oas.HealthCheckStatusOk}
+	if !ok {
+		c.Status = oas.HealthCheckStatusFail
+		c.Error = oas.NewOptString(reason)
+	}
+	return c
+}
+
+func (s Server) started() bool {
+	return time.Since(s.start) >= s.health.StartupDelay
+}
+
+func recordProbe(ctx context.Context, probe string, ok bool) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("simon.probe", probe),
+		attribute.Bool("simon.probe.ok", ok),
+	)
+}
+
+func (s Server) Healthz(ctx context.Context) (oas.HealthzRes, error) {
+	ok := !s.health.LivenessFlap.failing(time.Since(s.start))
+	recordProbe(ctx, "liveness", ok)
+	h := healthStatus(ok, probeCheck("flap", ok, "simulated liveness flap"))
+	if !ok {
+		return (*oas.HealthzServiceUnavailable)(&h), nil
+	}
+	return (*oas.HealthzOK)(&h), nil
+}
+
+func (s Server) Startupz(ctx context.Context) (oas.StartupzRes, error) {
+	ok := s.started()
+	recordProbe(ctx, "startup", ok)
+	h := healthStatus(ok, probeCheck("startup", ok, "simulated slow startup"))
+	if !ok {
+		return (*oas.StartupzServiceUnavailable)(&h), nil
+	}
+	return (*oas.StartupzOK)(&h), nil
+}
+
+func (s Server) Readyz(ctx context.Context) (oas.ReadyzRes, error) {
+	var (
+		started = s.started()
+		flap    = !s.health.ReadinessFlap.failing(time.Since(s.start))
+	)
+	h, ok := s.runChecks(ctx)
+	h.Checks = append(h.Checks,
+		probeCheck("startup", started, "simulated slow startup"),
+		probeCheck("flap", flap, "simulated readiness flap"),
+	)
+	ok = ok && started && flap
+	recordProbe(ctx, "readiness", ok)
+	if !ok {
+		h.Status = oas.HealthStatusFail
+		return (*oas.ReadyzServiceUnavailable)(&h), nil
+	}
+	return (*oas.ReadyzOK)(&h), nil
+}
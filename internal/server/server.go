@@ -10,25 +10,161 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/go-faster/errors"
 	"github.com/go-faster/sdk/zctx"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	otelbaggage "go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/go-faster/simon/internal/auth"
+	"github.com/go-faster/simon/internal/broker"
 	"github.com/go-faster/simon/internal/oas"
+	"github.com/go-faster/simon/internal/resp"
+	"github.com/go-faster/simon/internal/sim"
 )
 
-func NewServer(tracerProvider trace.TracerProvider) *Server {
-	return &Server{
-		trace: tracerProvider.Tracer("simon.server"),
+// Downstream steps of UploadFile.
+const (
+	DownstreamExternal  = "external"
+	DownstreamCurl      = "curl"
+	DownstreamShell     = "shell"
+	DownstreamDB        = "db"
+	DownstreamMessaging = "messaging"
+	DownstreamRPC       = "rpc"
+)
+
+// DefaultDownstream is default list of downstream steps.
+const DefaultDownstream = "external,curl,shell"
+
+// ParseDownstream parses comma-separated list of downstream steps.
+func ParseDownstream(s string) ([]string, error) {
+	var steps []string
+	for _, step := range strings.Split(s, ",") {
+		step = strings.TrimSpace(step)
+		switch step {
+		case "":
+			continue
+		case DownstreamExternal, DownstreamCurl, DownstreamShell,
+			DownstreamDB, DownstreamMessaging, DownstreamRPC:
+			steps = append(steps, step)
+		default:
+			return nil, errors.Errorf("unknown downstream %q", step)
+		}
+	}
+	return steps, nil
+}
+
+// uploadsTopic is messaging destination of upload events.
+const uploadsTopic = "simon.uploads"
+
+// Options of Server.
+type Options struct {
+	// Cache enables caching of upload hashes by content digest.
+	Cache *resp.Client
+	// CacheTTL is expiration of cached hashes.
+	CacheTTL time.Duration
+
+	// Broker enables consuming of BrokerTopic as member of BrokerGroup.
+	Broker      *broker.Client
+	BrokerTopic string
+	BrokerGroup string
+
+	Health HealthOptions
+
+	// Auth requires credentials of UploadFile if enabled.
+	Auth *auth.Authenticator
+
+	// DownloadMaxSize limits size of Download, DefaultDownloadMaxSize
+	// if zero.
+	DownloadMaxSize int64
+
+	// Downstream is ordered list of steps called by UploadFile,
+	// see ParseDownstream.
+	Downstream []string
+}
+
+func NewServer(tracerProvider trace.TracerProvider, opts Options) *Server {
+	s := &Server{
+		trace:    tracerProvider.Tracer("simon.server"),
+		db:       sim.NewDB(tracerProvider),
+		queue:    sim.NewQueue(tracerProvider),
+		rpc:      sim.NewRPC(tracerProvider, "simon.Hasher"),
+		cache:    opts.Cache,
+		cacheTTL: opts.CacheTTL,
+		broker:   opts.Broker,
+		topic:    opts.BrokerTopic,
+		group:    opts.BrokerGroup,
+		health:   opts.Health,
+		auth:     opts.Auth,
+		start:    time.Now(),
+
+		downloadMaxSize: opts.DownloadMaxSize,
+		downstream:      opts.Downstream,
+	}
+	if s.downloadMaxSize <= 0 {
+		s.downloadMaxSize = DefaultDownloadMaxSize
 	}
+	return s
 }
 
 // Server implements oas.Handler.
 type Server struct {
 	trace trace.Tracer
+
+	db    *sim.DB
+	queue *sim.Queue
+	rpc   *sim.RPC
+
+	cache    *resp.Client
+	cacheTTL time.Duration
+
+	broker *broker.Client
+	topic  string
+	group  string
+
+	health HealthOptions
+	auth   *auth.Authenticator
+	start  time.Time
+
+	downloadMaxSize int64
+
+	// downstream is ordered list of steps called by UploadFile.
+	downstream []string
+}
+
+// Run background workers, like queue consumers, until context is done.
+func (s Server) Run(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return s.queue.Consume(ctx, func(ctx context.Context, msg sim.Message) error {
+			zctx.From(ctx).Info("Consumed upload event", zap.ByteString("hash", msg.Body))
+			return s.db.Query(ctx, "INSERT", "hashes",
+				"INSERT INTO hashes (hash, created_at) VALUES ($1, now())",
+			)
+		})
+	})
+	if s.broker != nil {
+		g.Go(func() error {
+			return s.broker.Consume(ctx, s.topic, s.group, func(ctx context.Context, msg broker.Message) error {
+				zctx.From(ctx).Info("Consumed message",
+					zap.Int64("offset", msg.Offset),
+					zap.String("key", msg.Key),
+					zap.Duration("lag", time.Since(msg.Time)),
+				)
+				return s.db.Query(ctx, "INSERT", "events",
+					"INSERT INTO events (key, value) VALUES ($1, $2)",
+				)
+			})
+		})
+	}
+	return g.Wait()
 }
 
 func (s Server) getEnvDefault(key, def string) string {
@@ -39,6 +175,20 @@ func (s Server) getEnvDefault(key, def string) string {
 	return v
 }
 
+// propagationCarrier returns trace context and baggage of ctx
+// for subprocesses.
+func propagationCarrier(ctx context.Context) propagation.MapCarrier {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// externalContext returns ctx without baggage, which is not propagated
+// to third-party hosts, keeping trace context only.
+func externalContext(ctx context.Context) context.Context {
+	return otelbaggage.ContextWithoutBaggage(ctx)
+}
+
 func (s Server) makeExternalRequest(ctx context.Context) error {
 	// Make external request.
 	ctx, span := s.trace.Start(ctx, "Server.makeExternalRequest")
@@ -51,6 +201,8 @@ func (s Server) makeExternalRequest(ctx context.Context) error {
 		return errors.Wrap(err, "create external request")
 	}
 
+	otel.GetTextMapPropagator().Inject(externalContext(ctx), propagation.HeaderCarrier(req.Header))
+
 	span.AddEvent("Starting external request",
 		trace.WithAttributes(
 			attribute.String("url", req.URL.String()),
@@ -88,7 +240,11 @@ func (s Server) makeCurlRequest(ctx context.Context) error {
 
 	bufErr := new(bytes.Buffer)
 	buf := new(bytes.Buffer)
-	cmd := exec.CommandContext(ctx, "curl", "-s", uri, "-o", "-", "--max-time", "5") // #nosec G204
+	args := []string{"-s", uri, "-o", "-", "--max-time", "5"}
+	for k, v := range propagationCarrier(externalContext(ctx)) {
+		args = append(args, "-H", k+": "+v)
+	}
+	cmd := exec.CommandContext(ctx, "curl", args...) // #nosec G204
 	cmd.Stdout = buf
 	cmd.Stderr = bufErr
 
@@ -117,6 +273,11 @@ func (s Server) makeShellCommand(ctx context.Context) error {
 	bufErr := new(bytes.Buffer)
 	buf := new(bytes.Buffer)
 	cmd := exec.CommandContext(ctx, "sh", "-c", "echo hello && sleep 1 && echo world")
+	// Environment carrier, e.g. TRACEPARENT and BAGGAGE.
+	cmd.Env = os.Environ()
+	for k, v := range propagationCarrier(ctx) {
+		cmd.Env = append(cmd.Env, strings.ToUpper(k)+"="+v)
+	}
 	cmd.Stdout = buf
 	cmd.Stderr = bufErr
 
@@ -139,40 +300,123 @@ func (s Server) makeShellCommand(ctx context.Context) error {
 }
 
 func (s Server) UploadFile(ctx context.Context, req *oas.UploadFileReq) (*oas.UploadResponse, error) {
+	if s.auth != nil {
+		if err := s.auth.Require(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	ctx, span := s.trace.Start(ctx, "Server.UploadFile")
 	defer span.End()
 
-	iterations := req.Iterations.Or(1)
 	buf := new(bytes.Buffer)
 	if _, err := io.Copy(buf, req.File.File); err != nil {
 		return nil, errors.Wrap(err, "copy")
 	}
 
+	hash, err := s.upload(ctx, buf.Bytes(), req.Iterations.Or(1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &oas.UploadResponse{
+		Hash: hash,
+	}, nil
+}
+
+// upload hashes uploaded data and calls downstream, shared by HTTP and gRPC.
+func (s Server) upload(ctx context.Context, data []byte, iterations int) (string, error) {
 	zctx.From(ctx).Info("UploadFile",
 		zap.Int("iterations", iterations),
-		zap.Int("size", buf.Len()),
+		zap.Int("size", len(data)),
 	)
 
-	h := sha256.New()
-	for i := 0; i < iterations; i++ {
-		if _, err := h.Write(buf.Bytes()); err != nil {
-			return nil, errors.Wrap(err, "write")
+	hash, err := s.hash(ctx, data, iterations)
+	if err != nil {
+		return "", err
+	}
+	if err := s.callDownstream(ctx, hash); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// hash computes iterated hash of data, using cache if enabled.
+func (s Server) hash(ctx context.Context, data []byte, iterations int) (string, error) {
+	compute := func() (string, error) {
+		h := sha256.New()
+		for i := 0; i < iterations; i++ {
+			if _, err := h.Write(data); err != nil {
+				return "", errors.Wrap(err, "write")
+			}
 		}
+		return fmt.Sprintf("%x", h.Sum(nil)), nil
+	}
+	if s.cache == nil {
+		return compute()
 	}
 
-	if err := s.makeExternalRequest(ctx); err != nil {
-		return nil, errors.Wrap(err, "external request")
+	// Cache errors are not fatal, falling back to computing hash.
+	lg := zctx.From(ctx)
+	key := fmt.Sprintf("simon:upload:%x:%d", sha256.Sum256(data), iterations)
+	if v, ok, err := s.cache.Get(ctx, key); err != nil {
+		lg.Warn("Cache lookup failed", zap.Error(err))
+	} else if ok {
+		return string(v), nil
 	}
-	if err := s.makeCurlRequest(ctx); err != nil {
-		return nil, errors.Wrap(err, "curl request")
+	hash, err := compute()
+	if err != nil {
+		return "", err
 	}
-	if err := s.makeShellCommand(ctx); err != nil {
-		return nil, errors.Wrap(err, "shell command")
+	if err := s.cache.Set(ctx, key, []byte(hash), s.cacheTTL); err != nil {
+		lg.Warn("Cache store failed", zap.Error(err))
 	}
+	return hash, nil
+}
 
-	return &oas.UploadResponse{
-		Hash: fmt.Sprintf("%x", h.Sum(nil)),
-	}, nil
+func (s Server) callDownstream(ctx context.Context, hash string) error {
+	for _, step := range s.downstream {
+		switch step {
+		case DownstreamExternal:
+			if err := s.makeExternalRequest(ctx); err != nil {
+				return errors.Wrap(err, "external request")
+			}
+		case DownstreamCurl:
+			if err := s.makeCurlRequest(ctx); err != nil {
+				return errors.Wrap(err, "curl request")
+			}
+		case DownstreamShell:
+			if err := s.makeShellCommand(ctx); err != nil {
+				return errors.Wrap(err, "shell command")
+			}
+		case DownstreamDB:
+			if err := s.db.Query(ctx, "SELECT", "uploads",
+				"SELECT id, hash FROM uploads WHERE hash = $1",
+			); err != nil {
+				return errors.Wrap(err, "db query")
+			}
+			if err := s.db.Query(ctx, "INSERT", "uploads",
+				"INSERT INTO uploads (hash, size) VALUES ($1, $2)",
+			); err != nil {
+				return errors.Wrap(err, "db insert")
+			}
+		case DownstreamMessaging:
+			if err := s.queue.Publish(ctx, uploadsTopic, []byte(hash)); err != nil {
+				return errors.Wrap(err, "publish")
+			}
+		case DownstreamRPC:
+			if err := s.rpc.Call(ctx, "Verify", func(ctx context.Context) error {
+				return s.db.Query(ctx, "SELECT", "hashes",
+					"SELECT hash FROM hashes WHERE hash = $1",
+				)
+			}); err != nil {
+				return errors.Wrap(err, "rpc")
+			}
+		default:
+			return errors.Errorf("unknown downstream %q", step)
+		}
+	}
+	return nil
 }
 
 var _ oas.Handler = (*Server)(nil)
@@ -185,8 +429,19 @@ func (s Server) Status(ctx context.Context) (*oas.Status, error) {
 }
 
 func (s Server) NewError(_ context.Context, err error) *oas.ErrorStatusCode {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, auth.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, auth.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, ErrRangeNotSatisfiable):
+		code = http.StatusRequestedRangeNotSatisfiable
+	case errors.Is(err, ErrTooLarge):
+		code = http.StatusBadRequest
+	}
 	return &oas.ErrorStatusCode{
-		StatusCode: 500,
+		StatusCode: code,
 		Response: oas.Error{
 			Message: err.Error(),
 		},
@@ -0,0 +1,305 @@
+// Package shed implements server-side rate limiting and load shedding.
+package shed
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/go-faster/simon/internal/middleware"
+	"github.com/go-faster/simon/internal/oas"
+)
+
+// Rejection reasons.
+const (
+	ReasonRateLimit    = "rate_limit"
+	ReasonQueueFull    = "queue_full"
+	ReasonQueueTimeout = "queue_timeout"
+	ReasonLatency      = "latency"
+)
+
+// Options of Shedder, zero values disable protection.
+type Options struct {
+	// RPS and Burst of token bucket per client, rejecting with 429.
+	RPS   float64
+	Burst int
+	// KeyHeader identifies client by header instead of IP.
+	KeyHeader string
+
+	// MaxConcurrency is global limit of in-flight requests, excess
+	// waits in queue of QueueSize for QueueTimeout, rejecting with 503.
+	MaxConcurrency int
+	QueueSize      int
+	QueueTimeout   time.Duration
+
+	// LatencyTarget enables adaptive shedding with 503 when average latency
+	// of requests exceeds target, with probability growing with excess.
+	LatencyTarget time.Duration
+
+	// Skip requests, e.g. probes.
+	Skip func(r *http.Request) bool
+}
+
+func (o *Options) setDefaults() {
+	if o.Burst <= 0 {
+		o.Burst = max(1, int(math.Ceil(o.RPS)))
+	}
+	if o.QueueTimeout <= 0 {
+		o.QueueTimeout = time.Second
+	}
+}
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Shedder protects server from overload.
+type Shedder struct {
+	opts Options
+
+	mux     sync.Mutex
+	clients map[string]*client
+
+	sem    chan struct{}
+	queued atomic.Int64
+
+	// Exponentially weighted moving average of latency in nanoseconds.
+	latency atomic.Int64
+
+	rejected metric.Int64Counter
+	waited   metric.Float64Histogram
+}
+
+// New creates new Shedder.
+func New(opts Options, meterProvider metric.MeterProvider) (*Shedder, error) {
+	opts.setDefaults()
+	s := &Shedder{
+		opts:    opts,
+		clients: map[string]*client{},
+	}
+	if opts.MaxConcurrency > 0 {
+		s.sem = make(chan struct{}, opts.MaxConcurrency)
+	}
+	meter := meterProvider.Meter("simon.shed")
+	var err error
+	if s.rejected, err = meter.Int64Counter("simon.shed.rejected",
+		metric.WithDescription("Number of rejected requests"),
+	); err != nil {
+		return nil, errors.Wrap(err, "rejected")
+	}
+	if s.waited, err = meter.Float64Histogram("simon.shed.queue.duration",
+		metric.WithDescription("Duration of waiting in concurrency queue"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, errors.Wrap(err, "waited")
+	}
+	queued, err := meter.Int64ObservableGauge("simon.shed.queue.size",
+		metric.WithDescription("Number of requests waiting in concurrency queue"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "queued")
+	}
+	latency, err := meter.Float64ObservableGauge("simon.shed.latency",
+		metric.WithDescription("Moving average of request latency used for shedding"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "latency")
+	}
+	if _, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		o.ObserveInt64(queued, s.queued.Load())
+		o.ObserveFloat64(latency, time.Duration(s.latency.Load()).Seconds())
+		return nil
+	}, queued, latency); err != nil {
+		return nil, errors.Wrap(err, "register callback")
+	}
+	return s, nil
+}
+
+// Run evicts idle clients until context is done.
+func (s *Shedder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			s.mux.Lock()
+			for k, c := range s.clients {
+				if now.Sub(c.lastSeen) > time.Minute {
+					delete(s.clients, k)
+				}
+			}
+			s.mux.Unlock()
+		}
+	}
+}
+
+func (s *Shedder) key(r *http.Request) string {
+	if s.opts.KeyHeader != "" {
+		if v := r.Header.Get(s.opts.KeyHeader); v != "" {
+			return v
+		}
+	}
+	return middleware.ClientIP(r)
+}
+
+// allow takes token of client, returning delay until next one if none.
+func (s *Shedder) allow(key string) (time.Duration, bool) {
+	s.mux.Lock()
+	c, ok := s.clients[key]
+	if !ok {
+		c = &client{limiter: rate.NewLimiter(rate.Limit(s.opts.RPS), s.opts.Burst)}
+		s.clients[key] = c
+	}
+	c.lastSeen = time.Now()
+	s.mux.Unlock()
+
+	res := c.limiter.Reserve()
+	if d := res.Delay(); d > 0 {
+		res.Cancel()
+		return d, false
+	}
+	return 0, true
+}
+
+// shedLatency reports whether request should be shed by latency.
+func (s *Shedder) shedLatency() bool {
+	avg := time.Duration(s.latency.Load())
+	if avg <= s.opts.LatencyTarget {
+		return false
+	}
+	// Probability grows with excess, keeping some traffic to measure latency.
+	p := min(0.9, 1-float64(s.opts.LatencyTarget)/float64(avg))
+	return rand.Float64() < p // #nosec G404
+}
+
+func (s *Shedder) observe(d time.Duration) {
+	const alpha = 0.1
+	for {
+		old := s.latency.Load()
+		v := int64(alpha*float64(d) + (1-alpha)*float64(old))
+		if old == 0 {
+			v = int64(d)
+		}
+		if s.latency.CompareAndSwap(old, v) {
+			return
+		}
+	}
+}
+
+// reject responds with Error, setting Retry-After and span attributes.
+func (s *Shedder) reject(w http.ResponseWriter, r *http.Request, code int, reason string, retryAfter time.Duration, attrs ...attribute.KeyValue) {
+	ctx := r.Context()
+	seconds := max(1, int(math.Ceil(retryAfter.Seconds())))
+	attrs = append(attrs,
+		attribute.Bool("simon.shed.rejected", true),
+		attribute.String("simon.shed.reason", reason),
+		attribute.Int("simon.shed.retry_after", seconds),
+	)
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+	s.rejected.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+	zctx.From(ctx).Debug("Rejected request",
+		zap.String("reason", reason),
+		zap.Int("code", code),
+	)
+
+	body, _ := (&oas.Error{Message: "request rejected: " + reason}).MarshalJSON()
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
+
+// Middleware applies protections, in order: rate limit, adaptive shedding
+// and concurrency limit.
+func (s *Shedder) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.opts.Skip != nil && s.opts.Skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if s.opts.RPS > 0 {
+				key := s.key(r)
+				if d, ok := s.allow(key); !ok {
+					s.reject(w, r, http.StatusTooManyRequests, ReasonRateLimit, d,
+						attribute.String("simon.shed.key", key),
+						attribute.Float64("simon.shed.rps", s.opts.RPS),
+					)
+					return
+				}
+			}
+			if s.opts.LatencyTarget > 0 && s.shedLatency() {
+				s.reject(w, r, http.StatusServiceUnavailable, ReasonLatency, time.Second,
+					attribute.Float64("simon.shed.latency", time.Duration(s.latency.Load()).Seconds()),
+					attribute.Float64("simon.shed.latency_target", s.opts.LatencyTarget.Seconds()),
+				)
+				return
+			}
+			if s.sem != nil {
+				if !s.acquire(w, r) {
+					return
+				}
+				defer func() { <-s.sem }()
+			}
+			start := time.Now()
+			next.ServeHTTP(w, r)
+			s.observe(time.Since(start))
+		})
+	}
+}
+
+// acquire takes concurrency slot, waiting in queue.
+func (s *Shedder) acquire(w http.ResponseWriter, r *http.Request) bool {
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	default:
+	}
+	attrs := []attribute.KeyValue{
+		attribute.Int("simon.shed.max_concurrency", s.opts.MaxConcurrency),
+		attribute.Int("simon.shed.queue_size", s.opts.QueueSize),
+	}
+	if n := s.queued.Add(1); n > int64(s.opts.QueueSize) {
+		s.queued.Add(-1)
+		s.reject(w, r, http.StatusServiceUnavailable, ReasonQueueFull, time.Second, attrs...)
+		return false
+	}
+	defer s.queued.Add(-1)
+
+	ctx := r.Context()
+	start := time.Now()
+	timer := time.NewTimer(s.opts.QueueTimeout)
+	defer timer.Stop()
+	defer func() {
+		s.waited.Record(ctx, time.Since(start).Seconds())
+	}()
+	select {
+	case s.sem <- struct{}{}:
+		trace.SpanFromContext(ctx).SetAttributes(
+			attribute.Float64("simon.shed.queue.duration", time.Since(start).Seconds()),
+		)
+		return true
+	case <-timer.C:
+		s.reject(w, r, http.StatusServiceUnavailable, ReasonQueueTimeout, s.opts.QueueTimeout, attrs...)
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
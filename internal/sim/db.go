@@ -0,0 +1,59 @@
+package sim
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DB is simulated database client.
+type DB struct {
+	tracer  trace.Tracer
+	system  attribute.KeyValue
+	name    string
+	address string
+	port    int
+	latency *Latency
+}
+
+// NewDB creates new simulated PostgreSQL client.
+func NewDB(tracerProvider trace.TracerProvider) *DB {
+	return &DB{
+		tracer:  tracerProvider.Tracer("simon.sim.db"),
+		system:  semconv.DBSystemPostgreSQL,
+		name:    "simon",
+		address: "postgres.simon.svc.cluster.local",
+		port:    5432,
+		latency: newLatency(2*time.Millisecond, 20*time.Millisecond),
+	}
+}
+
+// Query simulates query execution, where operation is SQL keyword
+// like SELECT and table is the primary table of statement.
+func (d *DB) Query(ctx context.Context, operation, table, statement string) error {
+	ctx, span := d.tracer.Start(ctx, operation+" "+d.name+"."+table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			d.system,
+			semconv.DBName(d.name),
+			semconv.DBOperation(operation),
+			semconv.DBSQLTable(table),
+			semconv.DBStatement(statement),
+			semconv.ServerAddress(d.address),
+			semconv.ServerPort(d.port),
+			semconv.PeerService("postgres"),
+		),
+	)
+	defer span.End()
+
+	if err := d.latency.Wait(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
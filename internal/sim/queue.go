@@ -0,0 +1,132 @@
+package sim
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Message of simulated Queue.
+type Message struct {
+	ID          string
+	Destination string
+	Headers     map[string]string
+	Body        []byte
+}
+
+var errQueueFull = errors.New("queue is full")
+
+// Queue is simulated in-process Kafka-like queue.
+//
+// Producer injects trace context into message headers, consumer creates
+// new trace for each message, linked to the producer span.
+type Queue struct {
+	tracer  trace.Tracer
+	group   string
+	latency *Latency
+	ch      chan Message
+	seq     atomic.Int64
+}
+
+// NewQueue creates new simulated queue.
+func NewQueue(tracerProvider trace.TracerProvider) *Queue {
+	return &Queue{
+		tracer:  tracerProvider.Tracer("simon.sim.queue"),
+		group:   "simon",
+		latency: newLatency(time.Millisecond, 5*time.Millisecond),
+		ch:      make(chan Message, 1024),
+	}
+}
+
+func (q *Queue) propagator() propagation.TextMapPropagator {
+	return otel.GetTextMapPropagator()
+}
+
+// Publish message to destination.
+//
+// If queue is full, message is dropped and the drop is recorded on span,
+// like fire-and-forget producer does.
+func (q *Queue) Publish(ctx context.Context, destination string, body []byte) error {
+	id := strconv.FormatInt(q.seq.Add(1), 10)
+	ctx, span := q.tracer.Start(ctx, destination+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationPublish,
+			semconv.MessagingDestinationName(destination),
+			semconv.MessagingMessageID(id),
+			semconv.MessagingMessageBodySize(len(body)),
+			semconv.PeerService("kafka"),
+		),
+	)
+	defer span.End()
+
+	if err := q.latency.Wait(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	msg := Message{
+		ID:          id,
+		Destination: destination,
+		Headers:     map[string]string{},
+		Body:        body,
+	}
+	q.propagator().Inject(ctx, propagation.MapCarrier(msg.Headers))
+
+	select {
+	case q.ch <- msg:
+		return nil
+	default:
+		span.RecordError(errQueueFull)
+		span.SetStatus(codes.Error, errQueueFull.Error())
+		return nil
+	}
+}
+
+// Consume messages until context is done, calling handler for each one
+// in context of consumer span.
+func (q *Queue) Consume(ctx context.Context, handler func(ctx context.Context, msg Message) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-q.ch:
+			q.process(ctx, msg, handler)
+		}
+	}
+}
+
+func (q *Queue) process(ctx context.Context, msg Message, handler func(ctx context.Context, msg Message) error) {
+	producer := trace.SpanContextFromContext(
+		q.propagator().Extract(ctx, propagation.MapCarrier(msg.Headers)),
+	)
+	ctx, span := q.tracer.Start(ctx, msg.Destination+" deliver",
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(trace.Link{SpanContext: producer}),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationDeliver,
+			semconv.MessagingDestinationName(msg.Destination),
+			semconv.MessagingMessageID(msg.ID),
+			semconv.MessagingKafkaConsumerGroup(q.group),
+			semconv.MessagingMessageBodySize(len(msg.Body)),
+		),
+	)
+	defer span.End()
+
+	if err := handler(ctx, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
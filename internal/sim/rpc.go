@@ -0,0 +1,99 @@
+package sim
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// gRPC status codes used by simulation.
+const (
+	rpcCodeOK       = 0
+	rpcCodeInternal = 13
+)
+
+// RPC is simulated gRPC client and server pair.
+//
+// Client span context is propagated through simulated metadata,
+// so client and server spans form a regular parent-child pair.
+type RPC struct {
+	tracer  trace.Tracer
+	service string
+	address string
+	port    int
+	latency *Latency
+}
+
+// NewRPC creates new simulated RPC for service.
+func NewRPC(tracerProvider trace.TracerProvider, service string) *RPC {
+	return &RPC{
+		tracer:  tracerProvider.Tracer("simon.sim.rpc"),
+		service: service,
+		address: "rpc.simon.svc.cluster.local",
+		port:    9090,
+		latency: newLatency(time.Millisecond, 10*time.Millisecond),
+	}
+}
+
+// Call simulates unary call of method, with handler executed in
+// context of server span.
+func (r *RPC) Call(ctx context.Context, method string, handler func(ctx context.Context) error) error {
+	ctx, span := r.tracer.Start(ctx, r.service+"/"+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCService(r.service),
+			semconv.RPCMethod(method),
+			semconv.ServerAddress(r.address),
+			semconv.ServerPort(r.port),
+			semconv.PeerService(r.service),
+		),
+	)
+	defer span.End()
+
+	// Simulated network hop: only metadata crosses the boundary.
+	md := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, md)
+	if err := r.latency.Wait(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	err := r.serve(otel.GetTextMapPropagator().Extract(context.WithoutCancel(ctx), md), method, handler)
+	code := rpcCodeOK
+	if err != nil {
+		code = rpcCodeInternal
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(code))
+	return err
+}
+
+func (r *RPC) serve(ctx context.Context, method string, handler func(ctx context.Context) error) error {
+	ctx, span := r.tracer.Start(ctx, r.service+"/"+method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCService(r.service),
+			semconv.RPCMethod(method),
+		),
+	)
+	defer span.End()
+
+	err := handler(ctx)
+	code := rpcCodeOK
+	if err != nil {
+		code = rpcCodeInternal
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(code))
+	return err
+}
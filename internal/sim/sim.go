@@ -0,0 +1,51 @@
+// Package sim implements simulated downstream dependencies that emit
+// spans following OpenTelemetry semantic conventions.
+//
+// No real network calls are made, only latency is simulated.
+package sim
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Latency is uniformly distributed simulated latency.
+type Latency struct {
+	Min time.Duration
+	Max time.Duration
+
+	mux sync.Mutex
+	rnd *rand.Rand
+}
+
+// newLatency creates new Latency in [minimum, maximum].
+func newLatency(minimum, maximum time.Duration) *Latency {
+	return &Latency{
+		Min: minimum,
+		Max: maximum,
+		rnd: rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404
+	}
+}
+
+// Wait for random duration in [Min, Max] or until context is done.
+func (l *Latency) Wait(ctx context.Context) error {
+	d := l.Min
+	if l.Max > l.Min {
+		l.mux.Lock()
+		d += time.Duration(l.rnd.Int63n(int64(l.Max - l.Min)))
+		l.mux.Unlock()
+	}
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: simon/v1/simon.proto
+
+package simonpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SimonService_Status_FullMethodName     = "/simon.v1.SimonService/Status"
+	SimonService_UploadFile_FullMethodName = "/simon.v1.SimonService/UploadFile"
+)
+
+// SimonServiceClient is the client API for SimonService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Simon mirrors HTTP API over gRPC.
+type SimonServiceClient interface {
+	// Status returns server status.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// UploadFile uploads file in chunks and returns its hash.
+	UploadFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileRequest, UploadFileResponse], error)
+}
+
+type simonServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSimonServiceClient(cc grpc.ClientConnInterface) SimonServiceClient {
+	return &simonServiceClient{cc}
+}
+
+func (c *simonServiceClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, SimonService_Status_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simonServiceClient) UploadFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileRequest, UploadFileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SimonService_ServiceDesc.Streams[0], SimonService_UploadFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadFileRequest, UploadFileResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SimonService_UploadFileClient = grpc.ClientStreamingClient[UploadFileRequest, UploadFileResponse]
+
+// SimonServiceServer is the server API for SimonService service.
+// All implementations must embed UnimplementedSimonServiceServer
+// for forward compatibility.
+//
+// Simon mirrors HTTP API over gRPC.
+type SimonServiceServer interface {
+	// Status returns server status.
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	// UploadFile uploads file in chunks and returns its hash.
+	UploadFile(grpc.ClientStreamingServer[UploadFileRequest, UploadFileResponse]) error
+	mustEmbedUnimplementedSimonServiceServer()
+}
+
+// UnimplementedSimonServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSimonServiceServer struct{}
+
+func (UnimplementedSimonServiceServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedSimonServiceServer) UploadFile(grpc.ClientStreamingServer[UploadFileRequest, UploadFileResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
+}
+func (UnimplementedSimonServiceServer) mustEmbedUnimplementedSimonServiceServer() {}
+func (UnimplementedSimonServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeSimonServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SimonServiceServer will
+// result in compilation errors.
+type UnsafeSimonServiceServer interface {
+	mustEmbedUnimplementedSimonServiceServer()
+}
+
+func RegisterSimonServiceServer(s grpc.ServiceRegistrar, srv SimonServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSimonServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SimonService_ServiceDesc, srv)
+}
+
+func _SimonService_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimonServiceServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimonService_Status_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimonServiceServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimonService_UploadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SimonServiceServer).UploadFile(&grpc.GenericServerStream[UploadFileRequest, UploadFileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SimonService_UploadFileServer = grpc.ClientStreamingServer[UploadFileRequest, UploadFileResponse]
+
+// SimonService_ServiceDesc is the grpc.ServiceDesc for SimonService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SimonService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "simon.v1.SimonService",
+	HandlerType: (*SimonServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _SimonService_Status_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadFile",
+			Handler:       _SimonService_UploadFile_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "simon/v1/simon.proto",
+}
@@ -0,0 +1,226 @@
+package sink
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+	"google.golang.org/grpc/peer"
+)
+
+// Behavior of chaotic receiver for single signal.
+//
+// Ratios are probabilities in [0, 1] evaluated per export request.
+type Behavior struct {
+	// Delay before replying, plus random Jitter.
+	Delay  time.Duration `yaml:"delay"`
+	Jitter time.Duration `yaml:"jitter"`
+	// RejectRatio of requests is rejected with RESOURCE_EXHAUSTED for gRPC
+	// and 503 for HTTP, with RetryAfter hint.
+	RejectRatio float64       `yaml:"reject_ratio"`
+	RetryAfter  time.Duration `yaml:"retry_after"`
+	// DropRatio of requests is answered by closing the connection.
+	DropRatio float64 `yaml:"drop_ratio"`
+	// PartialRatio of items in accepted requests is reported as rejected
+	// through partial success.
+	PartialRatio float64 `yaml:"partial_ratio"`
+}
+
+// Phase applies per-signal behaviors for Duration.
+//
+// Key "*" matches any signal without explicit behavior.
+type Phase struct {
+	Duration time.Duration       `yaml:"duration"`
+	Signals  map[string]Behavior `yaml:"signals"`
+}
+
+// ChaosConfig is list of phases that are applied in order and repeated.
+//
+// Phase with zero duration lasts forever.
+type ChaosConfig struct {
+	Phases []Phase `yaml:"phases"`
+}
+
+// ReadChaosConfig reads ChaosConfig from yaml file.
+func ReadChaosConfig(name string) (ChaosConfig, error) {
+	var cfg ChaosConfig
+	data, err := os.ReadFile(name) // #nosec G304
+	if err != nil {
+		return cfg, errors.Wrap(err, "read")
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, errors.Wrap(err, "unmarshal")
+	}
+	return cfg, nil
+}
+
+type action int
+
+const (
+	actionAccept action = iota
+	actionReject
+	actionDrop
+)
+
+func (a action) String() string {
+	switch a {
+	case actionReject:
+		return "reject"
+	case actionDrop:
+		return "drop"
+	default:
+		return "accept"
+	}
+}
+
+// decision is chaos outcome for single request.
+type decision struct {
+	action     action
+	delay      time.Duration
+	retryAfter time.Duration
+	partial    float64
+}
+
+// Chaos decides how receiver misbehaves over time.
+type Chaos struct {
+	cfg   ChaosConfig
+	start time.Time
+
+	mux   sync.Mutex
+	rnd   *rand.Rand
+	conns map[string]net.Conn
+}
+
+// NewChaos creates new Chaos from config, starting first phase now.
+func NewChaos(cfg ChaosConfig) *Chaos {
+	return &Chaos{
+		cfg:   cfg,
+		start: time.Now(),
+		rnd:   rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404
+		conns: map[string]net.Conn{},
+	}
+}
+
+func (c *Chaos) phase(now time.Time) Phase {
+	var total time.Duration
+	for _, p := range c.cfg.Phases {
+		if p.Duration == 0 {
+			break
+		}
+		total += p.Duration
+	}
+	elapsed := now.Sub(c.start)
+	if total > 0 {
+		elapsed %= total
+	}
+	for _, p := range c.cfg.Phases {
+		if p.Duration == 0 || elapsed < p.Duration {
+			return p
+		}
+		elapsed -= p.Duration
+	}
+	return Phase{}
+}
+
+// Behavior returns current behavior for signal.
+func (c *Chaos) Behavior(signal string) Behavior {
+	p := c.phase(time.Now())
+	if b, ok := p.Signals[signal]; ok {
+		return b
+	}
+	return p.Signals["*"]
+}
+
+func (c *Chaos) decide(signal string) decision {
+	if c == nil {
+		return decision{}
+	}
+	b := c.Behavior(signal)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	d := decision{
+		delay:      b.Delay,
+		retryAfter: b.RetryAfter,
+		partial:    b.PartialRatio,
+	}
+	if b.Jitter > 0 {
+		d.delay += time.Duration(c.rnd.Int63n(int64(b.Jitter)))
+	}
+	switch v := c.rnd.Float64(); {
+	case v < b.RejectRatio:
+		d.action = actionReject
+	case v < b.RejectRatio+b.DropRatio:
+		d.action = actionDrop
+	}
+	return d
+}
+
+// Listener wraps ln, so gRPC connections can be dropped by chaos.
+func (c *Chaos) Listener(ln net.Listener) net.Listener {
+	if c == nil {
+		return ln
+	}
+	return &chaosListener{Listener: ln, chaos: c}
+}
+
+// dropConn closes connection of gRPC peer from ctx.
+func (c *Chaos) dropConn(ctx context.Context) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return
+	}
+	c.mux.Lock()
+	conn, ok := c.conns[p.Addr.String()]
+	c.mux.Unlock()
+	if ok {
+		_ = conn.Close()
+	}
+}
+
+type chaosListener struct {
+	net.Listener
+	chaos *Chaos
+}
+
+func (l *chaosListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	c := l.chaos
+	c.mux.Lock()
+	c.conns[conn.RemoteAddr().String()] = conn
+	c.mux.Unlock()
+	return &chaosConn{Conn: conn, chaos: c}, nil
+}
+
+type chaosConn struct {
+	net.Conn
+	chaos *Chaos
+}
+
+func (c *chaosConn) Close() error {
+	c.chaos.mux.Lock()
+	delete(c.chaos.conns, c.RemoteAddr().String())
+	c.chaos.mux.Unlock()
+	return c.Conn.Close()
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
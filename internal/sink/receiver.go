@@ -0,0 +1,304 @@
+package sink
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/encoding/gzip" // register gzip compressor
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/go-faster/simon/internal/generator"
+)
+
+const partialMessage = "rejected by simon sink chaos"
+
+// begin applies chaos delay and returns decision for request.
+func (s *Sink) begin(ctx context.Context, signal string) decision {
+	d := s.chaos.decide(signal)
+	sleep(ctx, d.delay)
+	s.outcomes.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("signal", signal),
+		attribute.String("action", d.action.String()),
+	))
+	return d
+}
+
+// grpcError returns error for rejected or dropped gRPC request.
+func (s *Sink) grpcError(ctx context.Context, d decision) error {
+	switch d.action {
+	case actionReject:
+		st := status.New(codes.ResourceExhausted, partialMessage)
+		if d.retryAfter > 0 {
+			if v, err := st.WithDetails(&errdetails.RetryInfo{
+				RetryDelay: durationpb.New(d.retryAfter),
+			}); err == nil {
+				st = v
+			}
+		}
+		return st.Err()
+	case actionDrop:
+		s.chaos.dropConn(ctx)
+		return status.Error(codes.Unavailable, "connection dropped by simon sink chaos")
+	default:
+		return nil
+	}
+}
+
+type traceService struct {
+	coltracepb.UnimplementedTraceServiceServer
+	sink *Sink
+}
+
+func (t traceService) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	d := t.sink.begin(ctx, generator.SignalTraces)
+	if err := t.sink.grpcError(ctx, d); err != nil {
+		return nil, err
+	}
+	return tracesResponse(t.sink.ConsumeTraces(ctx, req, proto.Size(req), d.partial)), nil
+}
+
+type metricsService struct {
+	colmetricspb.UnimplementedMetricsServiceServer
+	sink *Sink
+}
+
+func (m metricsService) Export(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	d := m.sink.begin(ctx, generator.SignalMetrics)
+	if err := m.sink.grpcError(ctx, d); err != nil {
+		return nil, err
+	}
+	return metricsResponse(m.sink.ConsumeMetrics(ctx, req, proto.Size(req), d.partial)), nil
+}
+
+type logsService struct {
+	collogspb.UnimplementedLogsServiceServer
+	sink *Sink
+}
+
+func (l logsService) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	d := l.sink.begin(ctx, generator.SignalLogs)
+	if err := l.sink.grpcError(ctx, d); err != nil {
+		return nil, err
+	}
+	return logsResponse(l.sink.ConsumeLogs(ctx, req, proto.Size(req), d.partial)), nil
+}
+
+func tracesResponse(rejected int64) *coltracepb.ExportTraceServiceResponse {
+	resp := &coltracepb.ExportTraceServiceResponse{}
+	if rejected > 0 {
+		resp.PartialSuccess = &coltracepb.ExportTracePartialSuccess{
+			RejectedSpans: rejected,
+			ErrorMessage:  partialMessage,
+		}
+	}
+	return resp
+}
+
+func metricsResponse(rejected int64) *colmetricspb.ExportMetricsServiceResponse {
+	resp := &colmetricspb.ExportMetricsServiceResponse{}
+	if rejected > 0 {
+		resp.PartialSuccess = &colmetricspb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: rejected,
+			ErrorMessage:       partialMessage,
+		}
+	}
+	return resp
+}
+
+func logsResponse(rejected int64) *collogspb.ExportLogsServiceResponse {
+	resp := &collogspb.ExportLogsServiceResponse{}
+	if rejected > 0 {
+		resp.PartialSuccess = &collogspb.ExportLogsPartialSuccess{
+			RejectedLogRecords: rejected,
+			ErrorMessage:       partialMessage,
+		}
+	}
+	return resp
+}
+
+// RegisterGRPC registers OTLP gRPC receivers on server.
+//
+// Use Chaos.Listener for the server listener to allow dropping connections.
+func (s *Sink) RegisterGRPC(srv *grpc.Server) {
+	coltracepb.RegisterTraceServiceServer(srv, traceService{sink: s})
+	colmetricspb.RegisterMetricsServiceServer(srv, metricsService{sink: s})
+	collogspb.RegisterLogsServiceServer(srv, logsService{sink: s})
+}
+
+// receiver returns OTLP HTTP handler for single signal.
+func receiver[Req proto.Message](
+	s *Sink,
+	signal string,
+	newReq func() Req,
+	consume func(ctx context.Context, req Req, size int, partial float64) int64,
+	response func(rejected int64) proto.Message,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d := s.begin(r.Context(), signal)
+		switch d.action {
+		case actionReject:
+			if d.retryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(d.retryAfter.Seconds())))))
+			}
+			http.Error(w, partialMessage, http.StatusServiceUnavailable)
+			return
+		case actionDrop:
+			// Aborts handler and closes the connection without response.
+			panic(http.ErrAbortHandler)
+		}
+
+		req := newReq()
+		size, ok := decodeRequest(w, r, req)
+		if !ok {
+			return
+		}
+		encodeResponse(w, r, response(consume(r.Context(), req, size, d.partial)))
+	}
+}
+
+// Handler returns http.Handler with OTLP HTTP receivers and stats API.
+func (s *Sink) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST /v1/traces", receiver(s, generator.SignalTraces,
+		func() *coltracepb.ExportTraceServiceRequest { return new(coltracepb.ExportTraceServiceRequest) },
+		s.ConsumeTraces,
+		func(rejected int64) proto.Message { return tracesResponse(rejected) },
+	))
+	mux.Handle("POST /v1/metrics", receiver(s, generator.SignalMetrics,
+		func() *colmetricspb.ExportMetricsServiceRequest { return new(colmetricspb.ExportMetricsServiceRequest) },
+		s.ConsumeMetrics,
+		func(rejected int64) proto.Message { return metricsResponse(rejected) },
+	))
+	mux.Handle("POST /v1/logs", receiver(s, generator.SignalLogs,
+		func() *collogspb.ExportLogsServiceRequest { return new(collogspb.ExportLogsServiceRequest) },
+		s.ConsumeLogs,
+		func(rejected int64) proto.Message { return logsResponse(rejected) },
+	))
+	mux.HandleFunc("GET /api/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		_ = e.Encode(s.Stats())
+	})
+	mux.HandleFunc("GET /api/traces", s.handleTraces)
+	return mux
+}
+
+// handleTraces returns stored traces as OTLP JSON TracesData.
+//
+// Query parameters "since" and "until" are unix seconds of first
+// received span, "limit" is maximum number of traces.
+func (s *Sink) handleTraces(w http.ResponseWriter, r *http.Request) {
+	var (
+		q     = r.URL.Query()
+		since = time.Time{}
+		until = time.Now()
+		limit = 0
+	)
+	if v := q.Get("since"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "bad since", http.StatusBadRequest)
+			return
+		}
+		since = time.Unix(n, 0)
+	}
+	if v := q.Get("until"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "bad until", http.StatusBadRequest)
+			return
+		}
+		until = time.Unix(n, 0)
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "bad limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	data, err := protojson.Marshal(s.traces.query(since, until, limit))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func isJSON(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "gzip")
+		}
+		defer func() {
+			_ = gr.Close()
+		}()
+		body = gr
+	}
+	return io.ReadAll(body)
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, msg proto.Message) (int, bool) {
+	data, err := readBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+	if isJSON(r) {
+		err = protojson.Unmarshal(data, msg)
+	} else {
+		err = proto.Unmarshal(data, msg)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+	return len(data), true
+}
+
+func encodeResponse(w http.ResponseWriter, r *http.Request, msg proto.Message) {
+	var (
+		data []byte
+		err  error
+	)
+	if isJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		data, err = protojson.Marshal(msg)
+	} else {
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		data, err = proto.Marshal(msg)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,60 @@
+package sink
+
+// seqTracker tracks received sequence numbers of single stream.
+//
+// Numbers below next are all received, only numbers above it are
+// stored explicitly, so memory stays bounded unless loss is permanent.
+type seqTracker struct {
+	next     int64
+	max      int64
+	ahead    map[int64]struct{}
+	received int64
+	unique   int64
+}
+
+func newSeqTracker() *seqTracker {
+	return &seqTracker{
+		max:   -1,
+		ahead: map[int64]struct{}{},
+	}
+}
+
+// Observe sequence number, returning true if it is duplicate.
+func (t *seqTracker) Observe(seq int64) (duplicate bool) {
+	t.received++
+	if seq < t.next {
+		return true
+	}
+	if _, ok := t.ahead[seq]; ok {
+		return true
+	}
+	t.unique++
+	if seq > t.max {
+		t.max = seq
+	}
+	if seq != t.next {
+		t.ahead[seq] = struct{}{}
+		return false
+	}
+	t.next++
+	for {
+		if _, ok := t.ahead[t.next]; !ok {
+			break
+		}
+		delete(t.ahead, t.next)
+		t.next++
+	}
+	return false
+}
+
+// Lost returns count of sequence numbers not yet received.
+//
+// Late data can still arrive and decrease it.
+func (t *seqTracker) Lost() int64 {
+	return t.max + 1 - t.unique
+}
+
+// Duplicates returns count of re-delivered sequence numbers.
+func (t *seqTracker) Duplicates() int64 {
+	return t.received - t.unique
+}
@@ -0,0 +1,393 @@
+// Package sink implements OTLP receiver that verifies delivered telemetry.
+package sink
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+
+	"github.com/go-faster/simon/internal/generator"
+)
+
+// Sink receives OTLP telemetry and keeps delivery summaries in memory.
+type Sink struct {
+	mux     sync.Mutex
+	signals map[string]*signalStats
+	chaos   *Chaos
+	traces  *traceStore
+
+	requests metric.Int64Counter
+	outcomes metric.Int64Counter
+	items    metric.Int64Counter
+	bytes    metric.Int64Counter
+	latency  metric.Float64Histogram
+}
+
+type signalStats struct {
+	requests int64
+	items    int64
+	bytes    int64
+	services map[string]int64
+	streams  map[string]*seqTracker
+
+	latencyCount int64
+	latencySum   time.Duration
+	latencyMax   time.Duration
+}
+
+// item is single received span, metric point or log record.
+type item struct {
+	service   string
+	generator string
+	seq       int64
+	hasSeq    bool
+	timestamp uint64
+}
+
+// Options of Sink.
+type Options struct {
+	// Chaos, if set, makes receivers misbehave.
+	Chaos *Chaos
+	// MaxTraces is number of recent traces kept for verification.
+	// Zero disables trace storage.
+	MaxTraces int
+}
+
+// New initializes and returns new Sink.
+func New(meterProvider metric.MeterProvider, opts Options) (*Sink, error) {
+	meter := meterProvider.Meter("simon.sink")
+	s := &Sink{
+		signals: map[string]*signalStats{},
+		chaos:   opts.Chaos,
+		traces:  newTraceStore(opts.MaxTraces),
+	}
+
+	var err error
+	if s.requests, err = meter.Int64Counter("simon.sink.requests",
+		metric.WithDescription("Number of received export requests"),
+	); err != nil {
+		return nil, errors.Wrap(err, "requests counter")
+	}
+	if s.outcomes, err = meter.Int64Counter("simon.sink.chaos",
+		metric.WithDescription("Number of export requests by chaos outcome"),
+	); err != nil {
+		return nil, errors.Wrap(err, "chaos counter")
+	}
+	if s.items, err = meter.Int64Counter("simon.sink.items",
+		metric.WithDescription("Number of received spans, metric points and log records"),
+	); err != nil {
+		return nil, errors.Wrap(err, "items counter")
+	}
+	if s.bytes, err = meter.Int64Counter("simon.sink.bytes",
+		metric.WithDescription("Size of received export requests"),
+		metric.WithUnit("By"),
+	); err != nil {
+		return nil, errors.Wrap(err, "bytes counter")
+	}
+	if s.latency, err = meter.Float64Histogram("simon.sink.latency",
+		metric.WithDescription("Time between telemetry timestamp and its delivery"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, errors.Wrap(err, "latency histogram")
+	}
+
+	lost, err := meter.Int64ObservableGauge("simon.sink.lost",
+		metric.WithDescription("Number of sequence numbers not received yet"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "lost gauge")
+	}
+	duplicates, err := meter.Int64ObservableGauge("simon.sink.duplicates",
+		metric.WithDescription("Number of re-delivered sequence numbers"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "duplicates gauge")
+	}
+	if _, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		for signal, st := range s.Stats().Signals {
+			attrs := metric.WithAttributes(attribute.String("signal", signal))
+			o.ObserveInt64(lost, st.Lost, attrs)
+			o.ObserveInt64(duplicates, st.Duplicates, attrs)
+		}
+		return nil
+	}, lost, duplicates); err != nil {
+		return nil, errors.Wrap(err, "register callback")
+	}
+
+	return s, nil
+}
+
+// Stats of received telemetry.
+type Stats struct {
+	Signals map[string]SignalStats `json:"signals"`
+}
+
+// SignalStats is delivery summary of single signal.
+type SignalStats struct {
+	Requests int64            `json:"requests"`
+	Items    int64            `json:"items"`
+	Bytes    int64            `json:"bytes"`
+	Services map[string]int64 `json:"services"`
+	// Lost and Duplicates are computed from generator sequence numbers.
+	Lost       int64        `json:"lost"`
+	Duplicates int64        `json:"duplicates"`
+	Latency    LatencyStats `json:"latency"`
+}
+
+// LatencyStats summarizes end-to-end latency in seconds.
+type LatencyStats struct {
+	Count int64   `json:"count"`
+	Mean  float64 `json:"mean"`
+	Max   float64 `json:"max"`
+}
+
+// Stats returns snapshot of received telemetry stats.
+func (s *Sink) Stats() Stats {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	out := Stats{Signals: map[string]SignalStats{}}
+	for signal, st := range s.signals {
+		v := SignalStats{
+			Requests: st.requests,
+			Items:    st.items,
+			Bytes:    st.bytes,
+			Services: map[string]int64{},
+			Latency: LatencyStats{
+				Count: st.latencyCount,
+				Max:   st.latencyMax.Seconds(),
+			},
+		}
+		if st.latencyCount > 0 {
+			v.Latency.Mean = st.latencySum.Seconds() / float64(st.latencyCount)
+		}
+		for k, n := range st.services {
+			v.Services[k] = n
+		}
+		for _, t := range st.streams {
+			v.Lost += t.Lost()
+			v.Duplicates += t.Duplicates()
+		}
+		out.Signals[signal] = v
+	}
+	return out
+}
+
+// observe records items accepted by receiver.
+func (s *Sink) observe(ctx context.Context, signal string, size int, items []item) {
+	now := time.Now()
+	signalAttr := attribute.String("signal", signal)
+	s.requests.Add(ctx, 1, metric.WithAttributes(signalAttr))
+	s.bytes.Add(ctx, int64(size), metric.WithAttributes(signalAttr))
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	st, ok := s.signals[signal]
+	if !ok {
+		st = &signalStats{
+			services: map[string]int64{},
+			streams:  map[string]*seqTracker{},
+		}
+		s.signals[signal] = st
+	}
+	st.requests++
+	st.bytes += int64(size)
+	st.items += int64(len(items))
+
+	perService := map[string]int64{}
+	for _, it := range items {
+		perService[it.service]++
+		if it.hasSeq && it.generator != "" {
+			t, ok := st.streams[it.generator]
+			if !ok {
+				t = newSeqTracker()
+				st.streams[it.generator] = t
+			}
+			t.Observe(it.seq)
+		}
+		if it.timestamp == 0 {
+			continue
+		}
+		latency := now.Sub(time.Unix(0, int64(it.timestamp)))
+		st.latencyCount++
+		st.latencySum += latency
+		if latency > st.latencyMax {
+			st.latencyMax = latency
+		}
+		s.latency.Record(ctx, latency.Seconds(), metric.WithAttributes(signalAttr))
+	}
+	for service, n := range perService {
+		st.services[service] += n
+		s.items.Add(ctx, n, metric.WithAttributes(
+			signalAttr,
+			attribute.String("service.name", service),
+		))
+	}
+}
+
+func resourceInfo(attrs []*commonpb.KeyValue) (service, generatorID string) {
+	for _, kv := range attrs {
+		switch kv.GetKey() {
+		case "service.name":
+			service = kv.GetValue().GetStringValue()
+		case generator.AttrGeneratorID:
+			generatorID = kv.GetValue().GetStringValue()
+		}
+	}
+	return service, generatorID
+}
+
+func findSeq(attrs []*commonpb.KeyValue) (int64, bool) {
+	for _, kv := range attrs {
+		if kv.GetKey() != generator.AttrSeq {
+			continue
+		}
+		if v, ok := kv.GetValue().GetValue().(*commonpb.AnyValue_IntValue); ok {
+			return v.IntValue, true
+		}
+	}
+	return 0, false
+}
+
+// accepted returns count of items accepted if partial ratio of them
+// is rejected, rejecting trailing items.
+func accepted(n int, partial float64) int {
+	return n - int(float64(n)*partial)
+}
+
+// ConsumeTraces records received traces, rejecting partial ratio of spans,
+// and returns count of rejected spans.
+func (s *Sink) ConsumeTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest, size int, partial float64) int64 {
+	var items []item
+	for _, rs := range req.GetResourceSpans() {
+		service, gen := resourceInfo(rs.GetResource().GetAttributes())
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				seq, hasSeq := findSeq(span.GetAttributes())
+				items = append(items, item{
+					service:   service,
+					generator: gen,
+					seq:       seq,
+					hasSeq:    hasSeq,
+					timestamp: span.GetEndTimeUnixNano(),
+				})
+			}
+		}
+	}
+	n := accepted(len(items), partial)
+	s.traces.add(time.Now(), req, n)
+	s.observe(ctx, generator.SignalTraces, size, items[:n])
+	return int64(len(items) - n)
+}
+
+type dataPoint interface {
+	GetAttributes() []*commonpb.KeyValue
+	GetTimeUnixNano() uint64
+}
+
+func metricPoints(m *metricspb.Metric) []dataPoint {
+	var points []dataPoint
+	switch {
+	case m.GetGauge() != nil:
+		for _, p := range m.GetGauge().GetDataPoints() {
+			points = append(points, p)
+		}
+	case m.GetSum() != nil:
+		for _, p := range m.GetSum().GetDataPoints() {
+			points = append(points, p)
+		}
+	case m.GetHistogram() != nil:
+		for _, p := range m.GetHistogram().GetDataPoints() {
+			points = append(points, p)
+		}
+	case m.GetExponentialHistogram() != nil:
+		for _, p := range m.GetExponentialHistogram().GetDataPoints() {
+			points = append(points, p)
+		}
+	case m.GetSummary() != nil:
+		for _, p := range m.GetSummary().GetDataPoints() {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// pointSeq finds sequence number of metric point, set by generator
+// in exemplar to keep point attributes stable.
+func pointSeq(p dataPoint) (int64, bool) {
+	if seq, ok := findSeq(p.GetAttributes()); ok {
+		return seq, true
+	}
+	if p, ok := p.(interface{ GetExemplars() []*metricspb.Exemplar }); ok {
+		for _, e := range p.GetExemplars() {
+			if seq, ok := findSeq(e.GetFilteredAttributes()); ok {
+				return seq, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// ConsumeMetrics records received metrics, rejecting partial ratio of
+// metric points, and returns count of rejected metric points.
+func (s *Sink) ConsumeMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest, size int, partial float64) int64 {
+	var items []item
+	for _, rm := range req.GetResourceMetrics() {
+		service, gen := resourceInfo(rm.GetResource().GetAttributes())
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				for _, p := range metricPoints(m) {
+					seq, hasSeq := pointSeq(p)
+					items = append(items, item{
+						service:   service,
+						generator: gen,
+						seq:       seq,
+						hasSeq:    hasSeq,
+						timestamp: p.GetTimeUnixNano(),
+					})
+				}
+			}
+		}
+	}
+	n := accepted(len(items), partial)
+	s.observe(ctx, generator.SignalMetrics, size, items[:n])
+	return int64(len(items) - n)
+}
+
+// ConsumeLogs records received logs, rejecting partial ratio of
+// log records, and returns count of rejected log records.
+func (s *Sink) ConsumeLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest, size int, partial float64) int64 {
+	var items []item
+	for _, rl := range req.GetResourceLogs() {
+		service, gen := resourceInfo(rl.GetResource().GetAttributes())
+		for _, sl := range rl.GetScopeLogs() {
+			for _, r := range sl.GetLogRecords() {
+				seq, hasSeq := findSeq(r.GetAttributes())
+				ts := r.GetTimeUnixNano()
+				if ts == 0 {
+					ts = r.GetObservedTimeUnixNano()
+				}
+				items = append(items, item{
+					service:   service,
+					generator: gen,
+					seq:       seq,
+					hasSeq:    hasSeq,
+					timestamp: ts,
+				})
+			}
+		}
+	}
+	n := accepted(len(items), partial)
+	s.observe(ctx, generator.SignalLogs, size, items[:n])
+	return int64(len(items) - n)
+}
@@ -0,0 +1,97 @@
+package sink
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// traceStore keeps spans of recently received traces, so they can be
+// fetched back for verification.
+type traceStore struct {
+	mux    sync.Mutex
+	max    int
+	traces map[string]*storedTrace
+	order  []string
+}
+
+type storedTrace struct {
+	received  time.Time
+	fragments []*tracepb.ResourceSpans
+}
+
+func newTraceStore(max int) *traceStore {
+	return &traceStore{
+		max:    max,
+		traces: map[string]*storedTrace{},
+	}
+}
+
+// add stores first n spans of req.
+func (s *traceStore) add(now time.Time, req *coltracepb.ExportTraceServiceRequest, n int) {
+	if s.max <= 0 {
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			byTrace := map[string][]*tracepb.Span{}
+			for _, span := range ss.GetSpans() {
+				if n <= 0 {
+					break
+				}
+				n--
+				id := hex.EncodeToString(span.GetTraceId())
+				byTrace[id] = append(byTrace[id], span)
+			}
+			for id, spans := range byTrace {
+				t, ok := s.traces[id]
+				if !ok {
+					t = &storedTrace{received: now}
+					s.traces[id] = t
+					s.order = append(s.order, id)
+				}
+				t.fragments = append(t.fragments, &tracepb.ResourceSpans{
+					Resource:  rs.GetResource(),
+					SchemaUrl: rs.GetSchemaUrl(),
+					ScopeSpans: []*tracepb.ScopeSpans{{
+						Scope:     ss.GetScope(),
+						SchemaUrl: ss.GetSchemaUrl(),
+						Spans:     spans,
+					}},
+				})
+			}
+		}
+	}
+	for len(s.order) > s.max {
+		delete(s.traces, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// query returns spans of traces first received in [since, until),
+// up to limit traces.
+func (s *traceStore) query(since, until time.Time, limit int) *tracepb.TracesData {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	out := &tracepb.TracesData{}
+	n := 0
+	for _, id := range s.order {
+		if limit > 0 && n >= limit {
+			break
+		}
+		t := s.traces[id]
+		if t.received.Before(since) || !t.received.Before(until) {
+			continue
+		}
+		out.ResourceSpans = append(out.ResourceSpans, t.fragments...)
+		n++
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+// Package slow implements slow clients and servers to exercise timeouts.
+package slow
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// reader limits read bandwidth.
+type reader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if b := r.limiter.Burst(); len(p) > b {
+		p = p[:b]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.limiter.WaitN(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// Reader limits read bandwidth of r to bytesPerSecond, reading at most
+// chunk bytes at once.
+func Reader(ctx context.Context, r io.Reader, bytesPerSecond, chunk int) io.Reader {
+	if chunk <= 0 {
+		chunk = bytesPerSecond
+	}
+	return &reader{
+		ctx:     ctx,
+		r:       r,
+		limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), chunk),
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// TransportOptions of bandwidth-limited transport, zero is no limit.
+type TransportOptions struct {
+	// Upload is request body bytes per second.
+	Upload int
+	// Download is response body bytes per second.
+	Download int
+	// Chunk is max bytes read at once, Upload or Download by default.
+	Chunk int
+}
+
+type transport struct {
+	next http.RoundTripper
+	opts TransportOptions
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if t.opts.Upload > 0 && req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(ctx)
+		req.Body = readCloser{
+			Reader: Reader(ctx, req.Body, t.opts.Upload, t.opts.Chunk),
+			Closer: req.Body,
+		}
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.opts.Download > 0 {
+		resp.Body = readCloser{
+			Reader: Reader(ctx, resp.Body, t.opts.Download, t.opts.Chunk),
+			Closer: resp.Body,
+		}
+	}
+	return resp, nil
+}
+
+// Transport wraps next, limiting bandwidth of request and response bodies.
+func Transport(next http.RoundTripper, opts TransportOptions) http.RoundTripper {
+	if opts.Upload <= 0 && opts.Download <= 0 {
+		return next
+	}
+	return &transport{next: next, opts: opts}
+}
@@ -0,0 +1,136 @@
+package slow
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-faster/simon/internal/middleware"
+)
+
+// ServerOptions of slow responses, zero values disable behavior.
+type ServerOptions struct {
+	// Ratio of slow responses, all responses if zero.
+	Ratio float64
+	// TrickleDelay is delay between TrickleChunk bytes of response body.
+	TrickleDelay time.Duration
+	TrickleChunk int
+	// StallFor is pause after StallAfter bytes of response body.
+	StallAfter int
+	StallFor   time.Duration
+}
+
+// Enabled reports whether any behavior is enabled.
+func (o ServerOptions) Enabled() bool {
+	return o.TrickleDelay > 0 || o.StallFor > 0
+}
+
+// Server writes responses slowly.
+type Server struct {
+	opts      ServerOptions
+	responses metric.Int64Counter
+}
+
+// NewServer creates new Server.
+func NewServer(opts ServerOptions, meterProvider metric.MeterProvider) (*Server, error) {
+	if opts.TrickleChunk <= 0 {
+		opts.TrickleChunk = 1
+	}
+	meter := meterProvider.Meter("simon.slow")
+	responses, err := meter.Int64Counter("simon.slow.responses",
+		metric.WithDescription("Number of slow responses"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "responses")
+	}
+	return &Server{opts: opts, responses: responses}, nil
+}
+
+// writer trickles or stalls response body.
+type writer struct {
+	http.ResponseWriter
+	ctx     context.Context
+	opts    ServerOptions
+	written int
+	stalled bool
+}
+
+func (w *writer) sleep(d time.Duration) error {
+	// Flush so client observes partial body.
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	var total int
+	for len(b) > 0 {
+		chunk := len(b)
+		if w.opts.TrickleDelay > 0 {
+			chunk = min(chunk, w.opts.TrickleChunk)
+		}
+		if w.opts.StallFor > 0 && !w.stalled {
+			if w.written >= w.opts.StallAfter {
+				w.stalled = true
+				if err := w.sleep(w.opts.StallFor); err != nil {
+					return total, err
+				}
+			} else {
+				chunk = min(chunk, w.opts.StallAfter-w.written)
+			}
+		}
+		n, err := w.ResponseWriter.Write(b[:chunk])
+		total += n
+		w.written += n
+		if err != nil {
+			return total, err
+		}
+		b = b[n:]
+		if w.opts.TrickleDelay > 0 && len(b) > 0 {
+			if err := w.sleep(w.opts.TrickleDelay); err != nil {
+				return total, err
+			}
+		}
+	}
+	return total, nil
+}
+
+// Unwrap is used by http.ResponseController.
+func (w *writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Middleware writes response bodies slowly.
+func (s *Server) Middleware() middleware.Middleware {
+	if !s.opts.Enabled() {
+		return nil
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.opts.Ratio > 0 && rand.Float64() >= s.opts.Ratio { // #nosec G404
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := r.Context()
+			attrs := []attribute.KeyValue{
+				attribute.Bool("simon.slow.trickle", s.opts.TrickleDelay > 0),
+				attribute.Bool("simon.slow.stall", s.opts.StallFor > 0),
+			}
+			trace.SpanFromContext(ctx).SetAttributes(attrs...)
+			s.responses.Add(ctx, 1, metric.WithAttributes(attrs...))
+			next.ServeHTTP(&writer{ResponseWriter: w, ctx: ctx, opts: s.opts}, r)
+		})
+	}
+}
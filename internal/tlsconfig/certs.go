@@ -0,0 +1,103 @@
+// Package tlsconfig implements TLS configuration, certificate generation
+// and handshake instrumentation.
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// Pair is PEM-encoded certificate and key.
+type Pair struct {
+	Cert []byte
+	Key  []byte
+}
+
+// X509KeyPair parses pair.
+func (p Pair) X509KeyPair() (tls.Certificate, error) {
+	return tls.X509KeyPair(p.Cert, p.Key)
+}
+
+const validity = 365 * 24 * time.Hour
+
+func generate(tmpl, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (Pair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return Pair{}, errors.Wrap(err, "generate key")
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return Pair{}, errors.Wrap(err, "serial")
+	}
+	tmpl.SerialNumber = serial
+	tmpl.NotBefore = time.Now().Add(-time.Minute)
+	tmpl.NotAfter = tmpl.NotBefore.Add(validity)
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return Pair{}, errors.Wrap(err, "create certificate")
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return Pair{}, errors.Wrap(err, "marshal key")
+	}
+	return Pair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// GenerateCA generates self-signed certificate authority.
+func GenerateCA(name string) (Pair, error) {
+	return generate(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, nil)
+}
+
+// Issue certificate signed by ca for hosts (DNS names or IPs), usable
+// by clients if client is set, otherwise by servers.
+func Issue(ca Pair, name string, hosts []string, client bool) (Pair, error) {
+	caCert, err := ca.X509KeyPair()
+	if err != nil {
+		return Pair{}, errors.Wrap(err, "parse ca")
+	}
+	parent, err := x509.ParseCertificate(caCert.Certificate[0])
+	if err != nil {
+		return Pair{}, errors.Wrap(err, "parse ca certificate")
+	}
+	parentKey, ok := caCert.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return Pair{}, errors.Errorf("unsupported ca key %T", caCert.PrivateKey)
+	}
+	tmpl := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: name},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if client {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	return generate(tmpl, parent, parentKey)
+}
@@ -0,0 +1,104 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"github.com/go-faster/errors"
+)
+
+// ServerOptions of server TLS.
+type ServerOptions struct {
+	// CertFile and KeyFile of server certificate, self-signed certificate
+	// for Hosts is generated if not set.
+	CertFile string
+	KeyFile  string
+	Hosts    []string
+	// ClientCAFile enables verification of client certificates (mTLS).
+	ClientCAFile string
+}
+
+func certPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file) // #nosec G304
+	if err != nil {
+		return nil, errors.Wrap(err, "read")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.Errorf("no certificates in %s", file)
+	}
+	return pool, nil
+}
+
+// Server creates server TLS config.
+func Server(opts ServerOptions) (*tls.Config, error) {
+	var cert tls.Certificate
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		var err error
+		if cert, err = tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile); err != nil {
+			return nil, errors.Wrap(err, "load key pair")
+		}
+	} else {
+		ca, err := GenerateCA("simon self-signed")
+		if err != nil {
+			return nil, errors.Wrap(err, "generate ca")
+		}
+		pair, err := Issue(ca, "simon", opts.Hosts, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "issue")
+		}
+		if cert, err = pair.X509KeyPair(); err != nil {
+			return nil, errors.Wrap(err, "parse key pair")
+		}
+	}
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if opts.ClientCAFile != "" {
+		pool, err := certPool(opts.ClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "client ca")
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// ClientOptions of client TLS.
+type ClientOptions struct {
+	// CAFile verifies server instead of system roots.
+	CAFile string
+	// CertFile and KeyFile of client certificate for mTLS.
+	CertFile   string
+	KeyFile    string
+	ServerName string
+	Insecure   bool
+}
+
+// Client creates client TLS config.
+func Client(opts ClientOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         opts.ServerName,
+		InsecureSkipVerify: opts.Insecure, // #nosec G402
+	}
+	if opts.CAFile != "" {
+		pool, err := certPool(opts.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "ca")
+		}
+		cfg.RootCAs = pool
+	}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "load key pair")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,206 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metrics records TLS handshakes.
+type Metrics struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+// NewMetrics creates new Metrics.
+func NewMetrics(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Metrics, error) {
+	m := &Metrics{
+		tracer: tracerProvider.Tracer("simon.tls"),
+	}
+	meter := meterProvider.Meter("simon.tls")
+	var err error
+	if m.duration, err = meter.Float64Histogram("simon.tls.handshake.duration",
+		metric.WithDescription("Duration of TLS handshake"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, errors.Wrap(err, "duration")
+	}
+	if m.errors, err = meter.Int64Counter("simon.tls.handshake.errors",
+		metric.WithDescription("Number of failed TLS handshakes"),
+	); err != nil {
+		return nil, errors.Wrap(err, "errors")
+	}
+	return m, nil
+}
+
+func versionName(v uint16) string {
+	switch v {
+	case tls.VersionTLS10:
+		return "1.0"
+	case tls.VersionTLS11:
+		return "1.1"
+	case tls.VersionTLS12:
+		return "1.2"
+	case tls.VersionTLS13:
+		return "1.3"
+	default:
+		return "unknown"
+	}
+}
+
+func stateAttributes(cs tls.ConnectionState) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("tls.protocol.version", versionName(cs.Version)),
+		attribute.Bool("tls.resumed", cs.DidResume),
+	}
+}
+
+func (m *Metrics) record(ctx context.Context, side string, d time.Duration, cs tls.ConnectionState, err error, extra ...attribute.KeyValue) []attribute.KeyValue {
+	attrs := append([]attribute.KeyValue{attribute.String("side", side)}, extra...)
+	if err != nil {
+		m.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+		return attrs
+	}
+	attrs = append(attrs, stateAttributes(cs)...)
+	m.duration.Record(ctx, d.Seconds(), metric.WithAttributes(attrs...))
+	return attrs
+}
+
+// handshakeTimeout limits server handshake, like ReadHeaderTimeout of
+// http.Server does when handshake is done by server.
+const handshakeTimeout = 10 * time.Second
+
+type accepted struct {
+	conn net.Conn
+	err  error
+}
+
+type listener struct {
+	net.Listener
+	cfg     *tls.Config
+	metrics *Metrics
+
+	accepted chan accepted
+	done     chan struct{}
+	close    sync.Once
+}
+
+func (l *listener) run() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			select {
+			case l.accepted <- accepted{err: err}:
+			case <-l.done:
+				return
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		go l.handshake(conn)
+	}
+}
+
+// handshake completes handshake before connection is accepted, so both
+// successful and failed handshakes are recorded.
+func (l *listener) handshake(conn net.Conn) {
+	ctx := context.Background()
+	start := time.Now()
+	_ = conn.SetDeadline(start.Add(handshakeTimeout))
+	tlsConn := tls.Server(conn, l.cfg)
+	err := tlsConn.HandshakeContext(ctx)
+	cs := tlsConn.ConnectionState()
+	l.metrics.record(ctx, "server", time.Since(start), cs, err,
+		attribute.Bool("tls.mutual", len(cs.PeerCertificates) > 0),
+	)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+	select {
+	case l.accepted <- accepted{conn: tlsConn}:
+	case <-l.done:
+		_ = tlsConn.Close()
+	}
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case a := <-l.accepted:
+		return a.conn, a.err
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *listener) Close() error {
+	l.close.Do(func() { close(l.done) })
+	return l.Listener.Close()
+}
+
+// Listener returns TLS listener recording server handshakes.
+//
+// Handshake is completed before Accept returns, and connections are
+// *tls.Conn, so http.Server negotiates protocol by ALPN as with ServeTLS.
+func (m *Metrics) Listener(ln net.Listener, cfg *tls.Config) net.Listener {
+	l := &listener{
+		Listener: ln,
+		cfg:      cfg,
+		metrics:  m,
+		accepted: make(chan accepted),
+		done:     make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+type transport struct {
+	next    http.RoundTripper
+	metrics *Metrics
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	var (
+		start time.Time
+		span  trace.Span
+	)
+	ct := &httptrace.ClientTrace{
+		TLSHandshakeStart: func() {
+			start = time.Now()
+			_, span = t.metrics.tracer.Start(ctx, "tls.handshake")
+		},
+		TLSHandshakeDone: func(cs tls.ConnectionState, err error) {
+			if span == nil {
+				return
+			}
+			defer span.End()
+			attrs := t.metrics.record(ctx, "client", time.Since(start), cs, err)
+			span.SetAttributes(attrs...)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+		},
+	}
+	return t.next.RoundTrip(req.WithContext(httptrace.WithClientTrace(ctx, ct)))
+}
+
+// Transport wraps next, recording client handshakes as spans.
+func (m *Metrics) Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next, metrics: m}
+}
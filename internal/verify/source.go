@@ -0,0 +1,264 @@
+package verify
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/go-faster/simon/internal/generator"
+)
+
+// Source fetches traces back from storage.
+type Source interface {
+	// Traces returns traces from [since, until) time window, up to limit.
+	Traces(ctx context.Context, since, until time.Time, limit int) ([]Trace, error)
+}
+
+func get(ctx context.Context, client *http.Client, u string, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "create request")
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := client.Do(req) // #nosec G704
+	if err != nil {
+		return nil, errors.Wrap(err, "do request")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024*1024))
+	if err != nil {
+		return nil, errors.Wrap(err, "read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s: status %d: %.512s", req.URL.Path, resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+func anyValue(v *commonpb.AnyValue) any {
+	switch v := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_IntValue:
+		return v.IntValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+	default:
+		return nil
+	}
+}
+
+// fromOTLP groups OTLP spans into traces.
+func fromOTLP(resourceSpans []*tracepb.ResourceSpans) []Trace {
+	byID := map[string]*Trace{}
+	var order []string
+	for _, rs := range resourceSpans {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, s := range ss.GetSpans() {
+				span := Span{
+					TraceID:    hex.EncodeToString(s.GetTraceId()),
+					SpanID:     hex.EncodeToString(s.GetSpanId()),
+					ParentID:   hex.EncodeToString(s.GetParentSpanId()),
+					Name:       s.GetName(),
+					Start:      time.Unix(0, int64(s.GetStartTimeUnixNano())),
+					End:        time.Unix(0, int64(s.GetEndTimeUnixNano())),
+					Attributes: map[string]any{},
+				}
+				for _, kv := range s.GetAttributes() {
+					span.Attributes[kv.GetKey()] = anyValue(kv.GetValue())
+				}
+				t, ok := byID[span.TraceID]
+				if !ok {
+					t = &Trace{ID: span.TraceID}
+					byID[span.TraceID] = t
+					order = append(order, span.TraceID)
+				}
+				t.Spans = append(t.Spans, span)
+			}
+		}
+	}
+	out := make([]Trace, 0, len(order))
+	for _, id := range order {
+		out = append(out, *byID[id])
+	}
+	return out
+}
+
+// SinkSource fetches traces from simon sink.
+type SinkSource struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+// Traces implements Source.
+func (s SinkSource) Traces(ctx context.Context, since, until time.Time, limit int) ([]Trace, error) {
+	q := url.Values{}
+	q.Set("since", strconv.FormatInt(since.Unix(), 10))
+	q.Set("until", strconv.FormatInt(until.Unix(), 10))
+	q.Set("limit", strconv.Itoa(limit))
+	data, err := get(ctx, s.Client, strings.TrimRight(s.BaseURL, "/")+"/api/traces?"+q.Encode(), "")
+	if err != nil {
+		return nil, err
+	}
+	var td tracepb.TracesData
+	if err := protojson.Unmarshal(data, &td); err != nil {
+		return nil, errors.Wrap(err, "decode")
+	}
+	return fromOTLP(td.GetResourceSpans()), nil
+}
+
+// TempoSource searches generated traces with TraceQL and fetches them
+// by ID using Tempo-compatible API.
+type TempoSource struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+// Traces implements Source.
+func (s TempoSource) Traces(ctx context.Context, since, until time.Time, limit int) ([]Trace, error) {
+	base := strings.TrimRight(s.BaseURL, "/")
+	q := url.Values{}
+	q.Set("q", fmt.Sprintf("{ span.%s > 0 }", generator.AttrSpanCount))
+	q.Set("start", strconv.FormatInt(since.Unix(), 10))
+	q.Set("end", strconv.FormatInt(until.Unix(), 10))
+	q.Set("limit", strconv.Itoa(limit))
+	data, err := get(ctx, s.Client, base+"/api/search?"+q.Encode(), "application/json")
+	if err != nil {
+		return nil, errors.Wrap(err, "search")
+	}
+	var search struct {
+		Traces []struct {
+			TraceID string `json:"traceID"`
+		} `json:"traces"`
+	}
+	if err := json.Unmarshal(data, &search); err != nil {
+		return nil, errors.Wrap(err, "decode search")
+	}
+
+	var out []Trace
+	for _, t := range search.Traces {
+		data, err := get(ctx, s.Client, base+"/api/traces/"+t.TraceID, "application/json")
+		if err != nil {
+			return nil, errors.Wrapf(err, "get trace %s", t.TraceID)
+		}
+		// Tempo returns resource spans as "batches".
+		var resp struct {
+			Batches []json.RawMessage `json:"batches"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, errors.Wrap(err, "decode trace")
+		}
+		var spans []*tracepb.ResourceSpans
+		for _, b := range resp.Batches {
+			rs := new(tracepb.ResourceSpans)
+			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, rs); err != nil {
+				return nil, errors.Wrap(err, "decode batch")
+			}
+			spans = append(spans, rs)
+		}
+		out = append(out, fromOTLP(spans)...)
+	}
+	return out, nil
+}
+
+// JaegerSource fetches traces of Service using Jaeger query API.
+type JaegerSource struct {
+	Client  *http.Client
+	BaseURL string
+	Service string
+}
+
+type jaegerTrace struct {
+	TraceID string `json:"traceID"`
+	Spans   []struct {
+		TraceID    string `json:"traceID"`
+		SpanID     string `json:"spanID"`
+		Operation  string `json:"operationName"`
+		References []struct {
+			RefType string `json:"refType"`
+			SpanID  string `json:"spanID"`
+		} `json:"references"`
+		StartTime int64 `json:"startTime"` // microseconds
+		Duration  int64 `json:"duration"`  // microseconds
+		Tags      []struct {
+			Key   string          `json:"key"`
+			Type  string          `json:"type"`
+			Value json.RawMessage `json:"value"`
+		} `json:"tags"`
+	} `json:"spans"`
+}
+
+// Traces implements Source.
+func (s JaegerSource) Traces(ctx context.Context, since, until time.Time, limit int) ([]Trace, error) {
+	q := url.Values{}
+	q.Set("service", s.Service)
+	q.Set("start", strconv.FormatInt(since.UnixMicro(), 10))
+	q.Set("end", strconv.FormatInt(until.UnixMicro(), 10))
+	q.Set("limit", strconv.Itoa(limit))
+	data, err := get(ctx, s.Client, strings.TrimRight(s.BaseURL, "/")+"/api/traces?"+q.Encode(), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data []jaegerTrace `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, errors.Wrap(err, "decode")
+	}
+
+	out := make([]Trace, 0, len(resp.Data))
+	for _, jt := range resp.Data {
+		t := Trace{ID: jt.TraceID}
+		for _, js := range jt.Spans {
+			span := Span{
+				TraceID:    js.TraceID,
+				SpanID:     js.SpanID,
+				Name:       js.Operation,
+				Start:      time.UnixMicro(js.StartTime),
+				End:        time.UnixMicro(js.StartTime + js.Duration),
+				Attributes: map[string]any{},
+			}
+			for _, ref := range js.References {
+				if ref.RefType == "CHILD_OF" {
+					span.ParentID = ref.SpanID
+					break
+				}
+			}
+			for _, tag := range js.Tags {
+				switch tag.Type {
+				case "int64":
+					var v int64
+					if err := json.Unmarshal(tag.Value, &v); err == nil {
+						span.Attributes[tag.Key] = v
+					}
+				default:
+					var v any
+					if err := json.Unmarshal(tag.Value, &v); err == nil {
+						span.Attributes[tag.Key] = fmt.Sprint(v)
+					}
+				}
+			}
+			t.Spans = append(t.Spans, span)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
@@ -0,0 +1,212 @@
+// Package verify implements trace completeness verification.
+package verify
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/simon/internal/generator"
+)
+
+// Span is backend-independent span representation.
+type Span struct {
+	TraceID  string
+	SpanID   string
+	ParentID string
+	Name     string
+	Start    time.Time
+	End      time.Time
+	// Attributes with string or int64 values.
+	Attributes map[string]any
+}
+
+func (s Span) intAttr(k string) (int64, bool) {
+	switch v := s.Attributes[k].(type) {
+	case int64:
+		return v, true
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Trace is set of spans with the same trace ID.
+type Trace struct {
+	ID    string
+	Spans []Span
+}
+
+// Report is verification result of single trace.
+type Report struct {
+	TraceID string `json:"trace_id"`
+	// Verifiable is false if trace does not carry expected topology,
+	// e.g. it was not produced by generator or its root is missing.
+	Verifiable     bool `json:"verifiable"`
+	RootMissing    bool `json:"root_missing"`
+	Expected       int  `json:"expected"`
+	Received       int  `json:"received"`
+	MissingSpans   int  `json:"missing_spans"`
+	BrokenLinks    int  `json:"broken_links"`
+	OrphanSpans    int  `json:"orphan_spans"`
+	ClockAnomalies int  `json:"clock_anomalies"`
+	DuplicateSpans int  `json:"duplicate_spans"`
+}
+
+// Complete reports whether trace has no anomalies.
+func (r Report) Complete() bool {
+	return !r.RootMissing &&
+		r.MissingSpans == 0 &&
+		r.BrokenLinks == 0 &&
+		r.OrphanSpans == 0 &&
+		r.ClockAnomalies == 0
+}
+
+// Verifier checks traces against topology they carry.
+type Verifier struct {
+	// ClockSkew is tolerated difference between child and parent bounds.
+	ClockSkew time.Duration
+}
+
+// Verify trace.
+func (v Verifier) Verify(t Trace) Report {
+	r := Report{TraceID: t.ID}
+
+	// Deduplicate spans by ID.
+	byID := make(map[string]Span, len(t.Spans))
+	for _, s := range t.Spans {
+		if _, ok := byID[s.SpanID]; ok {
+			r.DuplicateSpans++
+			continue
+		}
+		byID[s.SpanID] = s
+	}
+	r.Received = len(byID)
+
+	var (
+		root     *Span
+		hasIndex bool
+	)
+	for _, s := range byID {
+		if _, ok := s.intAttr(generator.AttrSpanIndex); ok {
+			hasIndex = true
+		}
+		if _, ok := s.Attributes[generator.AttrTopology]; ok {
+			root = &s
+		}
+	}
+
+	for _, s := range byID {
+		if s.End.Before(s.Start) {
+			r.ClockAnomalies++
+		}
+		if s.ParentID == "" {
+			continue
+		}
+		parent, ok := byID[s.ParentID]
+		if !ok {
+			r.OrphanSpans++
+			continue
+		}
+		if s.Start.Before(parent.Start.Add(-v.ClockSkew)) || s.End.After(parent.End.Add(v.ClockSkew)) {
+			r.ClockAnomalies++
+		}
+	}
+
+	if root == nil {
+		// Root is missing, but spans are from generator.
+		r.RootMissing = hasIndex
+		if hasIndex {
+			r.MissingSpans = 1
+		}
+		return r
+	}
+
+	topologyAttr, _ := root.Attributes[generator.AttrTopology].(string)
+	topology := strings.Split(topologyAttr, ",")
+	expected, ok := root.intAttr(generator.AttrSpanCount)
+	if !ok {
+		expected = int64(len(topology))
+	}
+	r.Verifiable = true
+	r.Expected = int(expected)
+
+	byIndex := map[int64]Span{}
+	for _, s := range byID {
+		if idx, ok := s.intAttr(generator.AttrSpanIndex); ok {
+			byIndex[idx] = s
+		}
+	}
+	for idx := int64(0); idx < expected; idx++ {
+		if _, ok := byIndex[idx]; !ok {
+			r.MissingSpans++
+		}
+	}
+	for idx, s := range byIndex {
+		if idx < 0 || idx >= int64(len(topology)) {
+			r.BrokenLinks++
+			continue
+		}
+		want, err := strconv.ParseInt(topology[idx], 10, 64)
+		if err != nil {
+			continue
+		}
+		if want < 0 {
+			if s.ParentID != "" {
+				r.BrokenLinks++
+			}
+			continue
+		}
+		parent, ok := byID[s.ParentID]
+		if !ok {
+			// Already counted as orphan.
+			continue
+		}
+		if got, ok := parent.intAttr(generator.AttrSpanIndex); !ok || got != want {
+			r.BrokenLinks++
+		}
+	}
+
+	return r
+}
+
+// Summary aggregates reports.
+type Summary struct {
+	Traces         int `json:"traces"`
+	Verifiable     int `json:"verifiable"`
+	Complete       int `json:"complete"`
+	Incomplete     int `json:"incomplete"`
+	RootMissing    int `json:"root_missing"`
+	MissingSpans   int `json:"missing_spans"`
+	BrokenLinks    int `json:"broken_links"`
+	OrphanSpans    int `json:"orphan_spans"`
+	ClockAnomalies int `json:"clock_anomalies"`
+	DuplicateSpans int `json:"duplicate_spans"`
+}
+
+// Add report to summary.
+func (s *Summary) Add(r Report) {
+	if !r.Verifiable && !r.RootMissing {
+		// Not a generated trace.
+		return
+	}
+	s.Traces++
+	if r.Verifiable {
+		s.Verifiable++
+	}
+	if r.Complete() {
+		s.Complete++
+	} else {
+		s.Incomplete++
+	}
+	if r.RootMissing {
+		s.RootMissing++
+	}
+	s.MissingSpans += r.MissingSpans
+	s.BrokenLinks += r.BrokenLinks
+	s.OrphanSpans += r.OrphanSpans
+	s.ClockAnomalies += r.ClockAnomalies
+	s.DuplicateSpans += r.DuplicateSpans
+}
@@ -0,0 +1,144 @@
+package workload
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/simon/internal/middleware"
+)
+
+const mib = 1 << 20
+
+// MemoryConfig of memory workload, zero values disable behavior.
+type MemoryConfig struct {
+	// Hold is MiB allocated and held on start.
+	Hold int
+	// Target is MiB to grow held memory to, with Growth MiB per second
+	// or immediately if Growth is zero.
+	Target int
+	Growth int
+	// Leak is KiB leaked on each request.
+	Leak int
+	// CrashAt is MiB of held memory that crashes process once crossed.
+	CrashAt int
+}
+
+// Enabled reports whether any behavior is enabled.
+func (c MemoryConfig) Enabled() bool {
+	return c.Hold > 0 || c.Target > 0 || c.Leak > 0
+}
+
+// Memory holds memory to simulate memory pressure and OOM kills.
+type Memory struct {
+	cfg MemoryConfig
+
+	mux    sync.Mutex
+	chunks [][]byte
+	held   int
+}
+
+// NewMemory creates new Memory.
+func NewMemory(cfg MemoryConfig, meterProvider metric.MeterProvider) (*Memory, error) {
+	m := &Memory{cfg: cfg}
+	meter := meterProvider.Meter("simon.workload")
+	held, err := meter.Int64ObservableGauge("simon.workload.memory.held",
+		metric.WithDescription("Memory intentionally held by workload"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "held")
+	}
+	if _, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		m.mux.Lock()
+		defer m.mux.Unlock()
+		o.ObserveInt64(held, int64(m.held))
+		return nil
+	}, held); err != nil {
+		return nil, errors.Wrap(err, "register callback")
+	}
+	return m, nil
+}
+
+// Held returns number of held bytes.
+func (m *Memory) Held() int {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.held
+}
+
+// alloc allocates and holds n bytes, touching every page so memory
+// is resident and counted by cgroup.
+func (m *Memory) alloc(ctx context.Context, n int) {
+	buf := make([]byte, n)
+	for i := 0; i < len(buf); i += 4096 {
+		buf[i] = 1
+	}
+
+	m.mux.Lock()
+	m.chunks = append(m.chunks, buf)
+	m.held += n
+	held := m.held
+	m.mux.Unlock()
+
+	if m.cfg.CrashAt > 0 && held >= m.cfg.CrashAt*mib {
+		zctx.From(ctx).Error("Memory threshold crossed, crashing",
+			zap.Int("held_mib", held/mib),
+			zap.Int("crash_at_mib", m.cfg.CrashAt),
+		)
+		// Panic in own goroutine is not recovered by net/http or SDK,
+		// crashing the process like runtime does.
+		go func() {
+			panic(fmt.Sprintf("simulated out of memory: holding %d MiB", held/mib))
+		}()
+		select {}
+	}
+}
+
+// Run allocates Hold MiB and grows to Target until context is done.
+func (m *Memory) Run(ctx context.Context) error {
+	if m.cfg.Hold > 0 {
+		m.alloc(ctx, m.cfg.Hold*mib)
+	}
+	if m.cfg.Target > 0 && m.cfg.Growth <= 0 {
+		if step := m.cfg.Target*mib - m.Held(); step > 0 {
+			m.alloc(ctx, step)
+		}
+	}
+	if m.cfg.Target <= 0 || m.cfg.Growth <= 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if step := min(m.cfg.Growth*mib, m.cfg.Target*mib-m.Held()); step > 0 {
+			m.alloc(ctx, step)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Middleware leaks Leak KiB on each request.
+func (m *Memory) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		if m.cfg.Leak <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.alloc(r.Context(), m.cfg.Leak*1024)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
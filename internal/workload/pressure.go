@@ -0,0 +1,344 @@
+package workload
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-faster/simon/internal/middleware"
+	"github.com/go-faster/simon/internal/oas"
+)
+
+// Runtime-pressure behaviors.
+const (
+	BehaviorMutex      = "mutex"
+	BehaviorChannel    = "channel"
+	BehaviorGC         = "gc"
+	BehaviorGoroutines = "goroutines"
+	BehaviorLeak       = "leak"
+)
+
+// BehaviorHeader selects behaviors of single request, overriding defaults,
+// e.g. "mutex=3,leak=1". Empty value disables behaviors.
+const BehaviorHeader = "X-Simon-Behavior"
+
+// BehaviorOptions select behaviors applied to requests.
+type BehaviorOptions struct {
+	// Defaults are applied to each request.
+	Defaults Behaviors
+	// Header enables overriding of Defaults by BehaviorHeader, which
+	// allows any client to leak memory and goroutines of server.
+	Header bool
+}
+
+// MaxIntensity limits intensity of single behavior, as header is set
+// by clients and e.g. leak intensity is number of leaked goroutines.
+const MaxIntensity = 100
+
+func knownBehavior(name string) bool {
+	switch name {
+	case BehaviorMutex, BehaviorChannel, BehaviorGC, BehaviorGoroutines, BehaviorLeak:
+		return true
+	default:
+		return false
+	}
+}
+
+// Behaviors maps behavior name to intensity.
+type Behaviors map[string]int
+
+// ParseBehaviors parses comma-separated list of name=intensity pairs,
+// intensity defaults to 1.
+func ParseBehaviors(s string) (Behaviors, error) {
+	b := Behaviors{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		intensity := 1
+		if ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, errors.Errorf("bad intensity of %q", name)
+			}
+			if n > MaxIntensity {
+				return nil, errors.Errorf("intensity of %q exceeds %d", name, MaxIntensity)
+			}
+			intensity = n
+		}
+		if !knownBehavior(name) {
+			return nil, errors.Errorf("unknown behavior %q", name)
+		}
+		b[name] = intensity
+	}
+	return b, nil
+}
+
+// String implements fmt.Stringer.
+func (b Behaviors) String() string {
+	parts := make([]string, 0, len(b))
+	for name, intensity := range b {
+		parts = append(parts, name+"="+strconv.Itoa(intensity))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// Pressure applies contention and runtime-pressure behaviors to requests.
+type Pressure struct {
+	tracer trace.Tracer
+
+	// Shared state for mutex behavior.
+	mux   sync.Mutex
+	state map[int]int
+
+	// Congested channel with single slow consumer.
+	congested chan struct{}
+	consumer  sync.Once
+
+	// Leaked goroutines block on leak forever.
+	leak   chan struct{}
+	leaked atomic.Int64
+
+	// Keeps GC pressure allocations reachable until next one.
+	gc atomic.Pointer[gcNode]
+
+	profiling sync.Once
+	applied   metric.Int64Counter
+}
+
+// NewPressure creates new Pressure.
+func NewPressure(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Pressure, error) {
+	p := &Pressure{
+		tracer:    tracerProvider.Tracer("simon.workload"),
+		state:     map[int]int{},
+		congested: make(chan struct{}, 1),
+		leak:      make(chan struct{}),
+	}
+	meter := meterProvider.Meter("simon.workload")
+	var err error
+	if p.applied, err = meter.Int64Counter("simon.workload.behaviors",
+		metric.WithDescription("Number of applied behaviors"),
+	); err != nil {
+		return nil, errors.Wrap(err, "behaviors")
+	}
+	leaked, err := meter.Int64ObservableGauge("simon.workload.leaked_goroutines",
+		metric.WithDescription("Number of intentionally leaked goroutines"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "leaked")
+	}
+	if _, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		o.ObserveInt64(leaked, p.leaked.Load())
+		return nil
+	}, leaked); err != nil {
+		return nil, errors.Wrap(err, "register callback")
+	}
+	return p, nil
+}
+
+// Apply behaviors in context of request.
+func (p *Pressure) Apply(ctx context.Context, b Behaviors) {
+	if len(b) == 0 {
+		return
+	}
+	p.profiling.Do(func() {
+		// Block and mutex profiles are empty unless sampling is enabled.
+		if runtime.SetMutexProfileFraction(-1) == 0 {
+			runtime.SetMutexProfileFraction(5)
+		}
+		runtime.SetBlockProfileRate(int(time.Millisecond / 10))
+	})
+	for _, name := range []string{
+		BehaviorMutex,
+		BehaviorChannel,
+		BehaviorGC,
+		BehaviorGoroutines,
+		BehaviorLeak,
+	} {
+		intensity := b[name]
+		if intensity <= 0 {
+			continue
+		}
+		func() {
+			ctx, span := p.tracer.Start(ctx, "behavior."+name, trace.WithAttributes(
+				attribute.String("simon.behavior", name),
+				attribute.Int("simon.behavior.intensity", intensity),
+			))
+			defer span.End()
+			p.apply(ctx, name, intensity)
+			p.applied.Add(ctx, 1, metric.WithAttributes(attribute.String("behavior", name)))
+		}()
+	}
+}
+
+func (p *Pressure) apply(ctx context.Context, name string, intensity int) {
+	switch name {
+	case BehaviorMutex:
+		p.contendMutex(intensity)
+	case BehaviorChannel:
+		p.congestChannel(ctx, intensity)
+	case BehaviorGC:
+		p.gcPressure(intensity)
+	case BehaviorGoroutines:
+		goroutineStorm(intensity)
+	case BehaviorLeak:
+		p.leakGoroutines(intensity)
+	}
+}
+
+// contendMutex updates shared state holding lock for intensity*100µs.
+func (p *Pressure) contendMutex(intensity int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	start := time.Now()
+	for i := 0; time.Since(start) < time.Duration(intensity)*100*time.Microsecond; i++ {
+		p.state[i%1024]++
+	}
+}
+
+// congestChannel sends intensity items to channel drained by single
+// consumer at 1ms per item.
+func (p *Pressure) congestChannel(ctx context.Context, intensity int) {
+	p.consumer.Do(func() {
+		go func() {
+			for range p.congested {
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	})
+	for i := 0; i < intensity; i++ {
+		select {
+		case p.congested <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+type gcNode struct {
+	next    *gcNode
+	payload [8]int64
+}
+
+// gcPressure allocates intensity MiB of short-lived pointer-rich objects.
+func (p *Pressure) gcPressure(intensity int) {
+	const nodeSize = 72
+	var head *gcNode
+	for i := 0; i < intensity*(1<<20)/nodeSize; i++ {
+		head = &gcNode{next: head}
+	}
+	p.gc.Store(head)
+	p.gc.Store(nil)
+}
+
+// goroutineStorm spawns intensity*100 short-lived goroutines and waits for them.
+func goroutineStorm(intensity int) {
+	var wg sync.WaitGroup
+	for i := 0; i < intensity*100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			time.Sleep(time.Millisecond)
+		}()
+	}
+	wg.Wait()
+}
+
+// leakGoroutines leaks intensity goroutines, each holding 64KiB.
+func (p *Pressure) leakGoroutines(intensity int) {
+	for i := 0; i < intensity; i++ {
+		p.leaked.Add(1)
+		go func() {
+			buf := make([]byte, 64*1024)
+			<-p.leak
+			_ = buf
+		}()
+	}
+}
+
+// Middleware applies defaults or, if enabled, behaviors from BehaviorHeader
+// to each request.
+func (p *Pressure) Middleware(opts BehaviorOptions) middleware.Middleware {
+	if len(opts.Defaults) == 0 && !opts.Header {
+		return nil
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b := opts.Defaults
+			if v, ok := r.Header[http.CanonicalHeaderKey(BehaviorHeader)]; ok && opts.Header {
+				parsed, err := ParseBehaviors(strings.Join(v, ","))
+				if err != nil {
+					body, _ := (&oas.Error{Message: errors.Wrap(err, "parse behaviors").Error()}).MarshalJSON()
+					w.Header().Set("Content-Type", "application/json; charset=utf-8")
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write(body)
+					return
+				}
+				b = parsed
+			}
+			p.Apply(r.Context(), b)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UnaryInterceptor is gRPC counterpart of Middleware, using lowercase
+// BehaviorHeader metadata.
+func (p *Pressure) UnaryInterceptor(opts BehaviorOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		b, err := grpcBehaviors(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		p.Apply(ctx, b)
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is gRPC counterpart of Middleware for streaming calls.
+func (p *Pressure) StreamInterceptor(opts BehaviorOptions) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		b, err := grpcBehaviors(ss.Context(), opts)
+		if err != nil {
+			return err
+		}
+		p.Apply(ss.Context(), b)
+		return handler(srv, ss)
+	}
+}
+
+// grpcBehaviors returns behaviors from metadata, rejecting malformed
+// ones with InvalidArgument like Middleware does with 400.
+func grpcBehaviors(ctx context.Context, opts BehaviorOptions) (Behaviors, error) {
+	if !opts.Header {
+		return opts.Defaults, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get(strings.ToLower(BehaviorHeader))
+	if len(v) == 0 {
+		return opts.Defaults, nil
+	}
+	b, err := ParseBehaviors(strings.Join(v, ","))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "parse behaviors").Error())
+	}
+	return b, nil
+}
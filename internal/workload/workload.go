@@ -0,0 +1,164 @@
+// Package workload implements profile-shaping workloads with known
+// flame graphs for validating profiling backends.
+//
+// Each workload runs in its own function with "simon.workload" pprof label,
+// and every cycle is a root span, so profiles can be linked to spans.
+package workload
+
+import (
+	"context"
+	"crypto/sha256"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+)
+
+// Config of workloads, zero values disable workload.
+type Config struct {
+	// CPU is fraction of single core burned in hot loop, in (0, 1].
+	CPU float64
+	// Alloc is rate of short-lived allocations in bytes per second.
+	Alloc int
+	// Lock is number of goroutines contending for single mutex.
+	Lock int
+}
+
+// Enabled reports whether any workload is enabled.
+func (c Config) Enabled() bool {
+	return c.CPU > 0 || c.Alloc > 0 || c.Lock > 0
+}
+
+const cycle = time.Second
+
+// sinks keep results of workloads, so compiler can't eliminate them.
+type sinks struct {
+	digest [sha256.Size]byte
+	alloc  [][]byte
+}
+
+// Run enabled workloads until context is done.
+func Run(ctx context.Context, tracerProvider trace.TracerProvider, cfg Config) error {
+	var (
+		tracer = tracerProvider.Tracer("simon.workload")
+		s      = new(sinks)
+	)
+	g, ctx := errgroup.WithContext(ctx)
+	run := func(name string, f func(ctx context.Context)) {
+		g.Go(func() error {
+			pprof.Do(ctx, pprof.Labels("simon.workload", name), func(ctx context.Context) {
+				ticker := time.NewTicker(cycle)
+				defer ticker.Stop()
+				for {
+					func() {
+						ctx, span := tracer.Start(ctx, "workload."+name,
+							trace.WithNewRoot(),
+							trace.WithAttributes(attribute.String("simon.workload", name)),
+						)
+						defer span.End()
+						f(ctx)
+					}()
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			})
+			return nil
+		})
+	}
+	if cfg.CPU > 0 {
+		run("cpu", func(ctx context.Context) { s.hotLoop(ctx, min(cfg.CPU, 1)) })
+	}
+	if cfg.Alloc > 0 {
+		run("alloc", func(ctx context.Context) { s.allocChurn(cfg.Alloc) })
+	}
+	if cfg.Lock > 0 {
+		// Mutex profile is empty unless sampling is enabled.
+		if runtime.SetMutexProfileFraction(-1) == 0 {
+			runtime.SetMutexProfileFraction(5)
+		}
+		run("lock", func(ctx context.Context) { lockContention(ctx, cfg.Lock) })
+	}
+	return g.Wait()
+}
+
+// hotLoop burns fraction of cycle in small slices.
+func (s *sinks) hotLoop(ctx context.Context, fraction float64) {
+	const slices = 10
+	busy := time.Duration(float64(cycle/slices) * fraction)
+	for i := 0; i < slices; i++ {
+		start := time.Now()
+		for time.Since(start) < busy {
+			for j := 0; j < 100; j++ {
+				s.hotLoopHash()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cycle/slices - busy):
+		}
+	}
+}
+
+// hotLoopHash is leaf of CPU flame graph.
+func (s *sinks) hotLoopHash() {
+	s.digest = sha256.Sum256(s.digest[:])
+}
+
+// allocChurn allocates n bytes in short-lived chunks.
+func (s *sinks) allocChurn(n int) {
+	const chunk = 4 * 1024
+	ring := make([][]byte, 64)
+	for i := 0; n > 0; i++ {
+		ring[i%len(ring)] = allocChunk(min(chunk, n))
+		n -= chunk
+	}
+	s.alloc = ring
+}
+
+// allocChunk is leaf of allocation flame graph.
+func allocChunk(n int) []byte {
+	b := make([]byte, n)
+	b[0] = 1
+	return b
+}
+
+// lockContention runs workers contending for single mutex during cycle.
+func lockContention(ctx context.Context, workers int) {
+	var (
+		mux     sync.Mutex
+		wg      sync.WaitGroup
+		counter int
+	)
+	ctx, cancel := context.WithTimeout(ctx, cycle/2)
+	defer cancel()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				lockContentionCritical(&mux, &counter)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// lockContentionCritical holds mutex for short busy period.
+func lockContentionCritical(mux *sync.Mutex, counter *int) {
+	mux.Lock()
+	defer mux.Unlock()
+	start := time.Now()
+	for time.Since(start) < 100*time.Microsecond {
+		for i := 0; i < 1000; i++ {
+			*counter++
+		}
+	}
+}
@@ -5,4 +5,6 @@ package simon
 import (
 	_ "github.com/ogen-go/ogen"
 	_ "github.com/ogen-go/ogen/middleware"
+	_ "google.golang.org/grpc/cmd/protoc-gen-go-grpc"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go"
 )